@@ -0,0 +1,47 @@
+package gotinycsv
+
+import (
+	"bytes"
+	"io"
+)
+
+var (
+	utf8BOM    = []byte{0xEF, 0xBB, 0xBF}
+	utf16LEBOM = []byte{0xFF, 0xFE}
+	utf16BEBOM = []byte{0xFE, 0xFF}
+)
+
+// skipBOM removes a byte order mark at the start of "r", and reports whether it is found.
+// The data with a UTF-16 LE or BE byte order mark is transcoded into UTF-8 by UTF16LE or UTF16BE.
+func skipBOM(r io.Reader) (io.Reader, bool) {
+	head := make([]byte, len(utf8BOM))
+	n, err := io.ReadFull(r, head)
+	head = head[:n]
+	var rest io.Reader = r
+	if err != nil {
+		// the error is returned again by the next read of "r"
+		rest = &errReader{r: r, err: err}
+	}
+	switch {
+	case bytes.Equal(head, utf8BOM):
+		return rest, true
+	case bytes.HasPrefix(head, utf16LEBOM):
+		return UTF16LE.NewReader(io.MultiReader(bytes.NewReader(head[2:]), rest)), true
+	case bytes.HasPrefix(head, utf16BEBOM):
+		return UTF16BE.NewReader(io.MultiReader(bytes.NewReader(head[2:]), rest)), true
+	}
+	return io.MultiReader(bytes.NewReader(head), rest), false
+}
+
+// errReader returns "err" instead of io.EOF and io.ErrUnexpectedEOF of io.ReadFull.
+type errReader struct {
+	r   io.Reader
+	err error
+}
+
+func (e *errReader) Read(p []byte) (int, error) {
+	if e.err == io.EOF || e.err == io.ErrUnexpectedEOF {
+		return 0, io.EOF
+	}
+	return 0, e.err
+}
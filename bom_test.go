@@ -0,0 +1,106 @@
+package gotinycsv
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func encodeUTF16(s string, order binary.AppendByteOrder, bom []byte) []byte {
+	b := append([]byte{}, bom...)
+	for _, u := range utf16.Encode([]rune(s)) {
+		b = order.AppendUint16(b, u)
+	}
+	return b
+}
+
+func skippedBOM(r io.Reader) io.Reader {
+	r, _ = skipBOM(r)
+	return r
+}
+
+func Test_skipBOM(t *testing.T) {
+	// normal case 1 (UTF-8)
+	{
+		r, bom := skipBOM(strings.NewReader("\ufeffNo,Name\n"))
+		assert.True(t, bom)
+		all, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		assert.Equal(t, "No,Name\n", string(all))
+
+		all, err = io.ReadAll(skippedBOM(strings.NewReader("N")))
+		assert.NoError(t, err)
+		assert.Equal(t, "N", string(all))
+	}
+	// normal case 2 (UTF-16 including a surrogate pair)
+	{
+		s := "No,Name\n1,Alex😀\n"
+		all, err := io.ReadAll(skippedBOM(bytes.NewReader(encodeUTF16(s, binary.LittleEndian, utf16LEBOM))))
+		assert.NoError(t, err)
+		assert.Equal(t, s, string(all))
+
+		all, err = io.ReadAll(skippedBOM(bytes.NewReader(encodeUTF16(s, binary.BigEndian, utf16BEBOM))))
+		assert.NoError(t, err)
+		assert.Equal(t, s, string(all))
+	}
+	// illegal case (broken UTF-16 is replaced by U+FFFD)
+	{
+		b := encodeUTF16("A", binary.LittleEndian, utf16LEBOM)
+		b = binary.LittleEndian.AppendUint16(b, 0xD800)
+		b = append(b, 'B')
+		all, err := io.ReadAll(skippedBOM(bytes.NewReader(b)))
+		assert.NoError(t, err)
+		assert.Equal(t, "A��", string(all))
+	}
+}
+
+func Test_Load_BOM(t *testing.T) {
+	csv := "\ufeffNo,Name\n1,Alex\n2,Bert\n"
+	type csventry struct {
+		No   int
+		Name string
+	}
+
+	// normal case 1 (header of maps)
+	{
+		entries := []map[string]string{}
+		assert.NoError(t, Load(strings.NewReader(csv), 1, 10, &entries))
+		assert.Equal(t, map[string]string{"No": "1", "Name": "Alex"}, entries[0])
+
+		dec := NewDecoder(strings.NewReader(csv), 1)
+		assert.True(t, dec.Next())
+		assert.Equal(t, []string{"No", "Name"}, dec.Header())
+	}
+	// normal case 2 (first cell of LoadVertically)
+	{
+		entries := []csventry{}
+		assert.NoError(t, LoadVertically(strings.NewReader("\ufeff1,2\nAlex,Bert\n"), 0, 0, 10, &entries))
+		assert.Equal(t, []csventry{{1, "Alex"}, {2, "Bert"}}, entries)
+	}
+	// normal case 3 (UTF-16)
+	{
+		entries := []csventry{}
+		b := encodeUTF16(csv[3:], binary.LittleEndian, utf16LEBOM)
+		assert.NoError(t, Load(bytes.NewReader(b), 1, 10, &entries))
+		assert.Equal(t, []csventry{{1, "Alex"}, {2, "Bert"}}, entries)
+
+		chunked := []csventry{}
+		assert.NoError(t, LoadReaderAt(bytes.NewReader(b), int64(len(b)), 1, 10, 2, &chunked))
+		assert.Equal(t, entries, chunked)
+	}
+	// normal case 4 (LoadReaderAt and Sniff)
+	{
+		entries := []csventry{}
+		assert.NoError(t, LoadReaderAt(strings.NewReader(csv), int64(len(csv)), 1, 10, 2, &entries))
+		assert.Equal(t, []csventry{{1, "Alex"}, {2, "Bert"}}, entries)
+
+		entries = []csventry{}
+		assert.NoError(t, LoadReaderAtWith(strings.NewReader(csv), int64(len(csv)), &entries, WithAuto(0)))
+		assert.Equal(t, 2, len(entries))
+	}
+}
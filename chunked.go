@@ -0,0 +1,301 @@
+package gotinycsv
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// chunk is a byte range of csv data aligned to record boundaries.
+type chunk struct {
+	start int64
+	end   int64
+	// line is the 1-based line number at "start".
+	line int
+}
+
+// skipRecords skips the "topmergin" records from the top of "ra" by a csv reader in the same way as Load,
+// so the skipped records may be malformed.
+// It returns the offset and the line number where the rest starts,
+// and the number of fields fixed by the first record read, which may be a skipped one.
+func skipRecords(ra io.ReaderAt, size int64, topmergin int, cfg *config) (int64, int, int, error) {
+	cr := cfg.newReader(io.NewSectionReader(ra, 0, size))
+	for i := 0; i < topmergin; i++ {
+		if _, err := cr.Read(); err == io.EOF {
+			return 0, 0, 0, fmt.Errorf("topmergin is too large")
+		}
+	}
+	start := cr.InputOffset()
+	// the first record to be loaded
+	_, err := cr.Read()
+	if err == io.EOF {
+		return 0, 0, 0, fmt.Errorf("topmergin is too large")
+	}
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	line := 1
+	buf := make([]byte, 64*1024)
+	for off := int64(0); off < start; {
+		l, err := ra.ReadAt(buf[:min(int64(len(buf)), start-off)], off)
+		if l == 0 && err != nil {
+			return 0, 0, 0, err
+		}
+		line += bytes.Count(buf[:l], []byte{'\n'})
+		off += int64(l)
+	}
+	return start, line, cr.FieldsPerRecord, nil
+}
+
+// splitChunks splits "ra" from "start" at "line" into "n" chunks aligned to record boundaries.
+// Boundaries are found by a single pass that only tracks double quotes, so newlines in quoted fields are handled correctly.
+func splitChunks(ra io.ReaderAt, size int64, start int64, line int, n int) ([]chunk, error) {
+	var targets []int64
+	for k := 1; k < n; k++ {
+		targets = append(targets, start+int64(k)*(size-start)/int64(n))
+	}
+	chunks := []chunk{{start: start, line: line}}
+
+	buf := make([]byte, 64*1024)
+	inQuote := false
+	for off := start; off < size; {
+		l, err := ra.ReadAt(buf[:min(int64(len(buf)), size-off)], off)
+		if l == 0 && err != nil {
+			return nil, err
+		}
+		for i, b := range buf[:l] {
+			pos := off + int64(i)
+			switch {
+			case b == '"':
+				inQuote = !inQuote
+			case b == '\n':
+				line++
+				if inQuote {
+					break
+				}
+				if len(targets) > 0 && pos+1 >= targets[0] && pos+1 < size {
+					chunks[len(chunks)-1].end = pos + 1
+					chunks = append(chunks, chunk{start: pos + 1, line: line})
+					for len(targets) > 0 && targets[0] <= pos+1 {
+						targets = targets[1:]
+					}
+				}
+			}
+		}
+		off += int64(l)
+	}
+	chunks[len(chunks)-1].end = size
+	return chunks, nil
+}
+
+// readChunk reads the records in "c", up to "limit" records if it is more than 0.
+// Reading is stopped without an error when "stop" returns true.
+// Except for ColumnsFirstRecord, each record is fitted to "width" columns by the column policy of "cfg",
+// otherwise it must have "fields" columns.
+// Line numbers of a parse error are corrected to the ones in the whole csv data.
+func readChunk(ra io.ReaderAt, c chunk, fields int, cfg *config, width int, explicit bool, limit int, stop func() bool) ([][]string, error) {
+	cr := cfg.newReader(io.NewSectionReader(ra, c.start, c.end-c.start))
+	if cfg.columns == ColumnsFirstRecord && cfg.dialect.FieldsPerRecord == 0 {
+		cr.FieldsPerRecord = fields
+	}
+	var records [][]string
+	var err error
+	for (limit <= 0 || len(records) < limit) && !stop() {
+		var record []string
+		record, err = cr.Read()
+		if err == nil {
+			record, err = fitRecord(cfg.columns, cr, record, width, explicit)
+		}
+		if err != nil {
+			break
+		}
+		records = append(records, record)
+	}
+	if err == io.EOF {
+		err = nil
+	}
+	var pe *csv.ParseError
+	if errors.As(err, &pe) {
+		pe.StartLine += c.line - 1
+		pe.Line += c.line - 1
+	}
+	return records, err
+}
+
+// Load a CSV from "ra" of "size" bytes, tokenizing and converting it concurrently.
+// The data is split into byte ranges aligned to record boundaries, which are parsed on "workers" goroutines.
+// If "workers" is 0 or less, runtime.GOMAXPROCS(0) is used.
+// The result is the same as Load, except that "out" is replaced by a new slice.
+// As Load, read errors and too large rows take precedence over conversion errors, and the earliest error of each kind is returned.
+// Each range is parsed up to "maxrows"+1 records, so too large rows are reported without parsing the whole data.
+// The elements of "out" must be struct or pointer to struct. Unlike Load, unexported fields are never set by default.
+// The other arguments are the same as Load.
+func LoadReaderAt(ra io.ReaderAt, size int64, topmergin int, maxrows int, workers int, out interface{}, ops ...string) error {
+	return LoadReaderAtWith(ra, size, out, WithSkipRows(topmergin), WithMaxRows(maxrows), WithWorkers(workers), withOps(ops))
+}
+
+// LoadReaderAtWith loads a CSV from "ra" of "size" bytes concurrently with options.
+// It is the same as LoadReaderAt except that the settings are given by "opts".
+func LoadReaderAtWith(ra io.ReaderAt, size int64, out interface{}, opts ...Option) error {
+	return loadReaderAt(ra, size, out, newConfig(opts...))
+}
+
+func loadReaderAt(ra io.ReaderAt, size int64, out interface{}, cfg *config) error {
+	if ra == nil {
+		return fmt.Errorf("reader is nil")
+	}
+	head := make([]byte, bzip2HeaderSize)
+	n, _ := ra.ReadAt(head, 0)
+	head = head[:n]
+	// the compressed data is decompressed sequentially
+	if c := cfg.compression; c == Gzip || c == Bzip2 || (c == CompressionAuto && detectCompression(head) != CompressionNone) {
+		return loadParallel(io.NewSectionReader(ra, 0, size), out, cfg)
+	}
+	// skips a UTF-8 byte order mark, and other ones are transcoded sequentially
+	switch bom := int64(len(utf8BOM)); {
+	case bytes.HasPrefix(head, utf8BOM):
+		ra, size = io.NewSectionReader(ra, bom, size-bom), size-bom
+		c := *cfg
+		c.dialect.Encoding, c.hasEncoding = UTF8, true
+		cfg = &c
+	case bytes.HasPrefix(head, utf16LEBOM) || bytes.HasPrefix(head, utf16BEBOM):
+		return loadParallel(io.NewSectionReader(ra, 0, size), out, cfg)
+	}
+	// the data to be transcoded is read sequentially
+	if cfg.dialect.Encoding != UTF8 {
+		return loadParallel(io.NewSectionReader(ra, 0, size), out, cfg)
+	}
+	if cfg.auto {
+		r, c, s, err := cfg.autoDetect(io.NewSectionReader(ra, 0, size), true, !cfg.hasEncoding)
+		if err != nil {
+			return err
+		}
+		if s.Encoding != UTF8 || s.LineEnding == "\r" {
+			return loadParallel(r, out, c)
+		}
+		cfg = c
+	}
+	if !cfg.dialect.chunkable() {
+		return loadParallel(io.NewSectionReader(ra, 0, size), out, cfg)
+	}
+	topmergin, maxrows, workers := cfg.topmergin, cfg.maxrows, cfg.workers
+	refp, err := sliceRefPointer(out)
+	if err != nil {
+		return err
+	}
+	elemt := refp.Type().Elem()
+	if isMapElem(elemt) {
+		return fmt.Errorf("elements of slice must be struct")
+	}
+	plan, err := checkedPlan(elemt, cfg)
+	if err != nil {
+		return err
+	}
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	// all records must have the same number of fields as the first one, unless the column policy is given
+	start, line, fields, err := skipRecords(ra, size, topmergin, cfg)
+	if err != nil {
+		return err
+	}
+	chunks, err := splitChunks(ra, size, start, line, workers)
+	if err != nil {
+		return err
+	}
+
+	// a chunk reads up to maxrows+1 records, which are enough to detect too many rows.
+	// If a chunk ends with a read error or too many rows, the result does not depend on the following chunks, so they are stopped.
+	// Conversion errors are reported after them as Load, so the following chunks are still read, but not converted.
+	limit := 0
+	if maxrows > 0 {
+		limit = maxrows + 1
+	}
+	var decided, failed atomic.Int64
+	decided.Store(int64(len(chunks)))
+	failed.Store(int64(len(chunks)))
+	lower := func(v *atomic.Int64, i int) {
+		for d := v.Load(); int64(i) < d && !v.CompareAndSwap(d, int64(i)); d = v.Load() {
+		}
+	}
+
+	records := make([][][]string, len(chunks))
+	slices := make([]reflect.Value, len(chunks))
+	errs := make([]error, len(chunks))
+	convErrs := make([]error, len(chunks))
+	var wg sync.WaitGroup
+	for i, c := range chunks {
+		wg.Add(1)
+		go func(i int, c chunk) {
+			defer wg.Done()
+			stop := func() bool { return int64(i) > decided.Load() }
+			records[i], errs[i] = readChunk(ra, c, fields, cfg, len(plan.columns), plan.explicit, limit, stop)
+			if errs[i] != nil || (limit > 0 && len(records[i]) >= limit) {
+				lower(&decided, i)
+				return
+			}
+			if stop() || int64(i) > failed.Load() {
+				return
+			}
+			slices[i], convErrs[i] = convertBatch(refp.Type(), plan, records[i], cfg)
+			if convErrs[i] != nil {
+				lower(&failed, i)
+			}
+		}(i, c)
+	}
+	wg.Wait()
+
+	rows := 0
+	for i := range chunks {
+		if errs[i] != nil {
+			return errs[i]
+		}
+		rows += len(records[i])
+		if maxrows > 0 && rows > maxrows {
+			return fmt.Errorf("rows are too large")
+		}
+	}
+	for _, err := range convErrs {
+		if err != nil {
+			return err
+		}
+	}
+
+	merged := reflect.MakeSlice(refp.Type(), 0, rows)
+	for _, s := range slices {
+		merged = reflect.AppendSlice(merged, s)
+	}
+	refp.Set(merged)
+
+	return nil
+}
+
+// LoadFile is LoadReaderAt for "f" of its whole size.
+func LoadFile(f *os.File, topmergin int, maxrows int, workers int, out interface{}, ops ...string) error {
+	return LoadFileWith(f, out, WithSkipRows(topmergin), WithMaxRows(maxrows), WithWorkers(workers), withOps(ops))
+}
+
+// LoadFileWith is LoadReaderAtWith for "f" of its whole size.
+func LoadFileWith(f *os.File, out interface{}, opts ...Option) error {
+	return loadFile(f, out, newConfig(opts...))
+}
+
+func loadFile(f *os.File, out interface{}, cfg *config) error {
+	if f == nil {
+		return fmt.Errorf("reader is nil")
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	return loadReaderAt(f, fi.Size(), out, cfg)
+}
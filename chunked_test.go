@@ -0,0 +1,183 @@
+package gotinycsv
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_skipRecords(t *testing.T) {
+	// normal case (skipped records are read as Load, even if they are malformed)
+	{
+		csv := "a,b\"\n\n1\n3\n"
+		start, line, fields, err := skipRecords(strings.NewReader(csv), int64(len(csv)), 1, newConfig())
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), start)
+		assert.Equal(t, 2, line)
+		assert.Equal(t, 1, fields)
+	}
+	// illegal case (too large topmergin)
+	{
+		csv := "a\nb\n\n"
+		_, _, _, err := skipRecords(strings.NewReader(csv), int64(len(csv)), 2, newConfig())
+
+		assert.EqualError(t, err, "topmergin is too large")
+	}
+}
+
+func Test_splitChunks(t *testing.T) {
+	// normal case (quoted newlines are not boundaries)
+	{
+		csv := "h\n\n\"a\nb\"\n\"c\n\nd\"\ne\n"
+		chunks, err := splitChunks(strings.NewReader(csv), int64(len(csv)), 2, 2, 4)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []chunk{{start: 2, end: 9, line: 2}, {start: 9, end: 16, line: 5}, {start: 16, end: 18, line: 8}}, chunks)
+	}
+}
+
+// countingReaderAt counts the bytes read from "ra".
+type countingReaderAt struct {
+	ra io.ReaderAt
+	n  atomic.Int64
+}
+
+func (c *countingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	n, err := c.ra.ReadAt(p, off)
+	c.n.Add(int64(n))
+	return n, err
+}
+
+func Test_LoadReaderAt(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString("No,Name,Note\n")
+	for i := 0; i < 1000; i++ {
+		fmt.Fprintf(&sb, "%d,name%d,\"line1\nline2,\"\"%d\"\"\"\n", i, i, i)
+	}
+	csv := sb.String()
+
+	type csventry struct {
+		No   int
+		Name string
+		Note string
+	}
+
+	// normal case 1 (same result as Load)
+	{
+		expected := []csventry{}
+		err := Load(strings.NewReader(csv), 1, 0, &expected)
+		assert.NoError(t, err)
+
+		for workers := 1; workers <= 8; workers++ {
+			entries := []csventry{}
+			err = LoadReaderAt(strings.NewReader(csv), int64(len(csv)), 1, 0, workers, &entries)
+
+			assert.NoError(t, err)
+			assert.Equal(t, expected, entries)
+		}
+	}
+	// normal case 2 (LoadFile)
+	{
+		name := filepath.Join(t.TempDir(), "test.csv")
+		assert.NoError(t, os.WriteFile(name, []byte(csv), 0o600))
+		f, err := os.Open(name)
+		assert.NoError(t, err)
+		defer f.Close()
+
+		entries := []*csventry{}
+		err = LoadFile(f, 1, 1000, 0, &entries)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1000, len(entries))
+		assert.Equal(t, "line1\nline2,\"999\"", entries[999].Note)
+	}
+	// illegal case 1 (line numbers of parse error)
+	{
+		csv := strings.Repeat("1,a\n", 500) + "2,b,c\n" + strings.Repeat("1,a\n", 500)
+		entries := []csventry{}
+		err := LoadReaderAt(strings.NewReader(csv), int64(len(csv)), 0, 0, 4, &entries)
+
+		assert.EqualError(t, err, "record on line 501: wrong number of fields")
+	}
+	// illegal case 2 (too large rows)
+	{
+		entries := []csventry{}
+		err := LoadReaderAt(strings.NewReader(csv), int64(len(csv)), 1, 999, 4, &entries)
+
+		assert.EqualError(t, err, "rows are too large")
+	}
+	// illegal case 3 (too large rows stop reading the chunks, and earlier errors win)
+	{
+		large := strings.Repeat("1,a\n", 200000)
+		ra := &countingReaderAt{ra: strings.NewReader(large)}
+		entries := []csventry{}
+		err := LoadReaderAt(ra, int64(len(large)), 0, 10, 4, &entries)
+
+		assert.EqualError(t, err, "rows are too large")
+		// records are not parsed beyond maxrows, apart from the boundary scan of splitChunks
+		assert.Less(t, ra.n.Load(), int64(len(large))+int64(len(large))/4)
+
+		broken := "1,a\n2,b,c\n" + large
+		err = LoadReaderAt(strings.NewReader(broken), int64(len(broken)), 0, 10, 4, &entries)
+		assert.EqualError(t, err, "record on line 2: wrong number of fields")
+	}
+	// illegal case 4 (read errors and too large rows precede conversion errors as Load)
+	{
+		var sb strings.Builder
+		sb.WriteString("1,zzz\n")
+		for i := 2; i < 1002; i++ {
+			fmt.Fprintf(&sb, "%d,active\n", i)
+		}
+		sb.WriteString("1002,\"active\n")
+		broken := sb.String()
+		type csventry struct {
+			No     int
+			Status testStatus
+		}
+		assert.NoError(t, RegisterEnum(map[string]testStatus{"active": testStatusActive}, false))
+
+		entries := []csventry{}
+		want := Load(strings.NewReader(broken), 0, 0, &entries)
+		assert.EqualError(t, want, "parse error on line 1002, column 14: extraneous or missing \" in quoted-field")
+		for workers := 1; workers <= 8; workers++ {
+			assert.EqualError(t, LoadReaderAt(strings.NewReader(broken), int64(len(broken)), 0, 0, workers, &entries), want.Error())
+		}
+
+		want = Load(strings.NewReader(broken), 0, 5, &entries)
+		assert.EqualError(t, want, "rows are too large")
+		assert.EqualError(t, LoadReaderAt(strings.NewReader(broken), int64(len(broken)), 0, 5, 4, &entries), want.Error())
+
+		// without them, the conversion error of the earliest row is returned
+		valid := broken[:strings.LastIndex(broken, "1002")]
+		assert.EqualError(t, LoadReaderAt(strings.NewReader(valid), int64(len(valid)), 0, 0, 4, &entries), "unknown label for gotinycsv.testStatus: zzz")
+	}
+	// illegal case 5 (a bare quote in a skipped header line)
+	{
+		broken := "a,b\"\n" + strings.Repeat("1,x\n", 100)
+		entries := []csventry{}
+		want := Load(strings.NewReader(broken), 1, 0, &entries)
+		assert.EqualError(t, want, "record on line 2: wrong number of fields")
+		assert.EqualError(t, LoadParallel(strings.NewReader(broken), 1, 0, 4, &entries), want.Error())
+		assert.EqualError(t, LoadReaderAt(strings.NewReader(broken), int64(len(broken)), 1, 0, 4, &entries), want.Error())
+
+		quoted := "x\"y\n" + strings.Repeat("1,x,\"z\"\n", 100)
+		assert.NoError(t, Load(strings.NewReader(quoted), 1, 0, &entries))
+		chunked := []csventry{}
+		assert.NoError(t, LoadReaderAt(strings.NewReader(quoted), int64(len(quoted)), 1, 0, 4, &chunked))
+		assert.Equal(t, entries, chunked)
+	}
+	// illegal case 6 (too large topmergin)
+	{
+		entries := []csventry{}
+		err := LoadReaderAt(strings.NewReader(csv), int64(len(csv)), 1001, 0, 4, &entries)
+
+		assert.EqualError(t, err, "topmergin is too large")
+	}
+}
@@ -0,0 +1,84 @@
+package gotinycsv
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ColumnPolicy specifies how records with a different number of columns from the structure are handled.
+type ColumnPolicy int
+
+const (
+	// ColumnsFirstRecord is the traditional behavior.
+	// All records must have the same number of columns as the first one, and more columns than the structure fields are an error.
+	ColumnsFirstRecord ColumnPolicy = iota
+	// ColumnsIgnoreExtra ignores the trailing columns bound to no field, and rejects records with missing columns.
+	ColumnsIgnoreExtra
+	// ColumnsPad ignores the trailing columns bound to no field, and pads missing cells as empty.
+	ColumnsPad
+	// ColumnsReject rejects records with extra or missing columns.
+	// Extra columns are still ignored if columns are bound by the index or "-" tag.
+	ColumnsReject
+)
+
+// DefaultColumns is the column policy of a load, which is fixed at the start of each call.
+// WithColumnPolicy sets the policy per call without changing it.
+var DefaultColumns = ColumnsFirstRecord
+
+var (
+	// ErrExtraColumns is reported in a *csv.ParseError for a record with extra columns.
+	ErrExtraColumns = errors.New("extra columns")
+	// ErrMissingColumns is reported in a *csv.ParseError for a record with missing columns.
+	ErrMissingColumns = errors.New("missing columns")
+)
+
+// newReader returns a csv reader for the dialect and the column policy of "cfg".
+// Except for ColumnsFirstRecord, the number of columns is checked by fitRecord instead of the reader.
+func (cfg *config) newReader(r io.Reader) *csv.Reader {
+	cr := csv.NewReader(r)
+	d := cfg.dialect
+	if d.Comma != 0 {
+		cr.Comma = d.Comma
+	}
+	cr.Comment = d.Comment
+	cr.LazyQuotes = d.LazyQuotes
+	cr.TrimLeadingSpace = d.TrimLeadingSpace
+	cr.FieldsPerRecord = d.FieldsPerRecord
+	if d.FieldsPerRecord == 0 && cfg.columns != ColumnsFirstRecord {
+		cr.FieldsPerRecord = -1
+	}
+	return cr
+}
+
+// fitRecord applies the column policy to "record" just read by "cr", for a structure bound to "width" columns.
+// The returned record has "width" columns except for ColumnsFirstRecord.
+// If "explicit" is true, the columns bound to no field are always ignored.
+func fitRecord(policy ColumnPolicy, cr *csv.Reader, record []string, width int, explicit bool) ([]string, error) {
+	if policy == ColumnsFirstRecord {
+		return record, nil
+	}
+	if len(record) > width {
+		if policy == ColumnsReject && !explicit {
+			return nil, columnError(cr, width, fmt.Errorf("%w (%d of %d)", ErrExtraColumns, len(record), width))
+		}
+		return record[:width], nil
+	}
+	if len(record) < width {
+		if policy != ColumnsPad {
+			return nil, columnError(cr, len(record)-1, fmt.Errorf("%w (%d of %d)", ErrMissingColumns, len(record), width))
+		}
+		for len(record) < width {
+			record = append(record, "")
+		}
+	}
+	return record, nil
+}
+
+// columnError returns the error at the "field"-th field of the record just read by "cr".
+func columnError(cr *csv.Reader, field int, err error) error {
+	start, _ := cr.FieldPos(0)
+	line, column := cr.FieldPos(field)
+	return &csv.ParseError{StartLine: start, Line: line, Column: column, Err: err}
+}
@@ -0,0 +1,128 @@
+package gotinycsv
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ColumnPolicy(t *testing.T) {
+	csv := `No,Name,Age
+1,Alex,41,extra
+2,Bert
+3,Carl,43
+`
+	type csventry struct {
+		No   int
+		Name string
+		Age  int
+	}
+
+	// normal case 1 (ColumnsPad)
+	{
+		DefaultColumns = ColumnsPad
+		entries := []csventry{{Age: 99}, {Age: 99}}
+		err := Load(strings.NewReader(csv), 1, 10, &entries)
+		DefaultColumns = ColumnsFirstRecord
+
+		assert.NoError(t, err)
+		assert.Equal(t, []csventry{{1, "Alex", 41}, {2, "Bert", 0}, {3, "Carl", 43}}, entries)
+	}
+	// normal case 2 (ColumnsPad for the other loaders)
+	{
+		DefaultColumns = ColumnsPad
+		rows := []csventry{}
+		dec := NewDecoder(strings.NewReader(csv), 1)
+		for dec.Next() {
+			var entry csventry
+			assert.NoError(t, dec.Decode(&entry))
+			rows = append(rows, entry)
+		}
+		parallel := []csventry{}
+		perr := LoadParallel(strings.NewReader(csv), 1, 10, 2, &parallel)
+		chunked := []csventry{}
+		cerr := LoadReaderAt(strings.NewReader(csv), int64(len(csv)), 1, 10, 2, &chunked)
+		var columns struct {
+			No   []int
+			Name []string
+			Age  []int
+		}
+		lerr := LoadColumns(strings.NewReader(csv), 1, 10, &columns)
+		DefaultColumns = ColumnsFirstRecord
+
+		expected := []csventry{{1, "Alex", 41}, {2, "Bert", 0}, {3, "Carl", 43}}
+		assert.Equal(t, expected, rows)
+		assert.NoError(t, perr)
+		assert.Equal(t, expected, parallel)
+		assert.NoError(t, cerr)
+		assert.Equal(t, expected, chunked)
+		assert.NoError(t, lerr)
+		assert.Equal(t, []int{41, 0, 43}, columns.Age)
+	}
+	// normal case 3 (ColumnsIgnoreExtra)
+	{
+		csv := `1,Alex,41,extra
+2,Bert,42
+`
+		DefaultColumns = ColumnsIgnoreExtra
+		entries := []csventry{}
+		err := Load(strings.NewReader(csv), 0, 10, &entries)
+		DefaultColumns = ColumnsFirstRecord
+
+		assert.NoError(t, err)
+		assert.Equal(t, []csventry{{1, "Alex", 41}, {2, "Bert", 42}}, entries)
+	}
+	// normal case 4 (maps are fitted to the header)
+	{
+		DefaultColumns = ColumnsPad
+		entries := []map[string]string{}
+		err := Load(strings.NewReader(csv), 1, 10, &entries)
+		DefaultColumns = ColumnsFirstRecord
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"No": "2", "Name": "Bert", "Age": ""}, entries[1])
+	}
+	// normal case 5 (per call)
+	{
+		entries := []csventry{}
+		assert.NoError(t, LoadWith(strings.NewReader(csv), &entries, WithSkipRows(1), WithColumnPolicy(ColumnsPad)))
+		assert.Equal(t, []csventry{{1, "Alex", 41}, {2, "Bert", 0}, {3, "Carl", 43}}, entries)
+		assert.Equal(t, ColumnsFirstRecord, DefaultColumns)
+		assert.Error(t, Load(strings.NewReader(csv), 1, 10, &entries))
+	}
+	// illegal case 1 (ColumnsIgnoreExtra rejects missing columns)
+	{
+		DefaultColumns = ColumnsIgnoreExtra
+		entries := []csventry{}
+		err := Load(strings.NewReader(csv), 1, 10, &entries)
+		DefaultColumns = ColumnsFirstRecord
+
+		assert.EqualError(t, err, "parse error on line 3, column 3: missing columns (2 of 3)")
+		assert.True(t, errors.Is(err, ErrMissingColumns))
+	}
+	// illegal case 2 (ColumnsReject rejects extra columns with the position)
+	{
+		DefaultColumns = ColumnsReject
+		entries := []csventry{}
+		err := Load(strings.NewReader(csv), 1, 10, &entries)
+		var rows int
+		ferr := ForEach(strings.NewReader(csv), 1, func(row *csventry, pos Position) error {
+			rows++
+			return nil
+		})
+		DefaultColumns = ColumnsFirstRecord
+
+		assert.EqualError(t, err, "parse error on line 2, column 11: extra columns (4 of 3)")
+		assert.True(t, errors.Is(err, ErrExtraColumns))
+		assert.EqualError(t, ferr, "parse error on line 2, column 11: extra columns (4 of 3)")
+		assert.Equal(t, 0, rows)
+	}
+	// illegal case 3 (ColumnsFirstRecord is the encoding/csv error)
+	{
+		entries := []csventry{}
+		err := Load(strings.NewReader(csv), 1, 10, &entries)
+		assert.EqualError(t, err, "record on line 2: wrong number of fields")
+	}
+}
@@ -0,0 +1,140 @@
+package gotinycsv
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"unsafe"
+)
+
+func structRefPointer(i interface{}) (*reflect.Value, error) {
+	ref := reflect.ValueOf(i)
+	if ref.Kind() != reflect.Ptr {
+		return nil, fmt.Errorf("failed to obtain a reference to i (did you forget &?)")
+	}
+	refp := ref.Elem()
+	if refp.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("i reference does not point to a struct")
+	}
+	return &refp, nil
+}
+
+// eachColumnFieldRefs returns references to the slice fields of the struct "ref".
+// The slices are truncated to length 0, keeping their capacity.
+// The references of the fields tagged with "-" and the fields skipped in safe mode are invalid (zero) values.
+func eachColumnFieldRefs(ref reflect.Value, specs []fieldSpec, policy UnexportedPolicy) ([]reflect.Value, error) {
+	refs := make([]reflect.Value, ref.NumField())
+	for i := range refs {
+		if specs[i].skip {
+			continue
+		}
+		sf := ref.Type().Field(i)
+		if policy != UnexportedSet && (sf.Name == "_" || !sf.IsExported()) {
+			if sf.Name != "_" && policy == UnexportedError {
+				return nil, fmt.Errorf("unexported field %s cannot be set in safe mode", sf.Name)
+			}
+			continue
+		}
+		field := ref.Field(i)
+		ft := field.Type()
+		if ft.Kind() != reflect.Slice || !isSupportedType(ft.Elem()) {
+			return nil, fmt.Errorf("Unsupported types are used in structure fields")
+		}
+		if policy == UnexportedSet {
+			field = reflect.NewAt(ft, unsafe.Pointer(field.UnsafeAddr())).Elem()
+		}
+		field.SetLen(0)
+		refs[i] = field
+	}
+	return refs, nil
+}
+
+// Load a CSV column by column.
+// "out" is a pointer to a struct whose fields are slices, such as struct{ Age []int64; Height []float64 }.
+// Each csv field is appended to the slice field at the same position as its column,
+// or the field bound to the column by the "#N" or "index=N" tag. Fields tagged with "-" are left untouched.
+// The other arguments are the same as Load.
+// This function does not emit an error if the conversion from a csv field to a structure field fails,
+// except for unknown labels of the types registered by RegisterEnum.
+// Unexported fields are handled in safe mode (see DefaultUnexported).
+func LoadColumns(r io.Reader, topmergin int, maxrows int, out interface{}, ops ...string) error {
+	return LoadColumnsWith(r, out, WithSkipRows(topmergin), WithMaxRows(maxrows), withOps(ops))
+}
+
+// LoadColumnsWith loads a CSV column by column with options.
+// It is the same as LoadColumns except that the settings are given by "opts".
+func LoadColumnsWith(r io.Reader, out interface{}, opts ...Option) error {
+	return loadColumns(r, out, newConfig(opts...))
+}
+
+func loadColumns(r io.Reader, out interface{}, cfg *config) error {
+	if r == nil {
+		return fmt.Errorf("reader is nil")
+	}
+	r, cfg, err := cfg.input(r, true)
+	if err != nil {
+		return err
+	}
+	topmergin, maxrows := cfg.topmergin, cfg.maxrows
+	refp, err := structRefPointer(out)
+	if err != nil {
+		return err
+	}
+
+	specs, err := structFieldSpecs(refp.Type())
+	if err != nil {
+		return err
+	}
+	columns, explicit, err := columnBindings(specs)
+	if err != nil {
+		return err
+	}
+
+	// create slice of references to slice field
+	fields, err := eachColumnFieldRefs(*refp, specs, cfg.unexported)
+	if err != nil {
+		return err
+	}
+
+	cr := cfg.newReader(r)
+
+	rows := 0
+	for ; ; rows++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if rows < topmergin {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if maxrows > 0 && rows >= topmergin+maxrows {
+			return fmt.Errorf("rows are too large")
+		}
+		if record, err = fitRecord(cfg.columns, cr, record, len(columns), explicit); err != nil {
+			return err
+		}
+		if !explicit && len(columns) < len(record) {
+			return fmt.Errorf("number of fields in the defined structure may not match the number of fields in the CSV.")
+		}
+		// appends csv record to "out" via references
+		for c, v := range record {
+			if c >= len(columns) || columns[c] < 0 || !fields[columns[c]].IsValid() {
+				continue
+			}
+			i := columns[c]
+			col := fields[i]
+			col.Set(reflect.Append(col, reflect.Zero(col.Type().Elem())))
+			if err = setEntityViaRef(col.Index(col.Len()-1), cfg.timelayout, specs[i].trimmed(v, cfg)); err != nil {
+				return err
+			}
+		}
+	}
+	if rows <= topmergin {
+		return fmt.Errorf("topmergin is too large")
+	}
+
+	return nil
+}
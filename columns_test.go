@@ -0,0 +1,93 @@
+package gotinycsv
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_LoadColumns(t *testing.T) {
+	csv := `No,Name,Age,Height,Birth
+1,Alex,41,74.5,1999.01.01
+2,Bert,42,68,2001.02.02
+3,Carl,32,70,2002.05.05
+`
+	// normal case 1
+	{
+		columns := struct {
+			_      []int
+			Name   []string
+			Age    []int64
+			Height []float64
+			Birth  []time.Time
+		}{}
+		err := LoadColumns(strings.NewReader(csv), 1, 10, &columns)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Alex", "Bert", "Carl"}, columns.Name)
+		assert.Equal(t, []int64{41, 42, 32}, columns.Age)
+		assert.Equal(t, []float64{74.5, 68, 70}, columns.Height)
+		assert.Equal(t, "2002-05-05 00:00:00 +0000 UTC", columns.Birth[2].String())
+	}
+	// normal case 2 (existing slices are overwritten)
+	{
+		columns := struct {
+			No   []int8
+			Name []string
+		}{No: []int8{9, 9, 9, 9, 9}}
+		err := LoadColumns(strings.NewReader("1,a\n2,b\n"), 0, 0, &columns)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int8{1, 2}, columns.No)
+		assert.Equal(t, []string{"a", "b"}, columns.Name)
+	}
+	// illegal case 1 (did not pass struct pointer)
+	{
+		columns := []struct{}{}
+		err := LoadColumns(strings.NewReader(csv), 1, 10, &columns)
+
+		assert.EqualError(t, err, "i reference does not point to a struct")
+	}
+	// illegal case 2 (field is not slice)
+	{
+		columns := struct {
+			No int
+		}{}
+		err := LoadColumns(strings.NewReader(csv), 1, 10, &columns)
+
+		assert.EqualError(t, err, "Unsupported types are used in structure fields")
+	}
+	// illegal case 3 (struct fields less than CSV fields)
+	{
+		columns := struct {
+			No []int
+		}{}
+		err := LoadColumns(strings.NewReader(csv), 1, 10, &columns)
+
+		assert.EqualError(t, err, "number of fields in the defined structure may not match the number of fields in the CSV.")
+	}
+	// illegal case 4 (too large rows)
+	{
+		columns := struct {
+			No   []int
+			Name []string
+			Age  []int
+			H    []float32
+			B    []time.Time
+		}{}
+		err := LoadColumns(strings.NewReader(csv), 1, 2, &columns)
+
+		assert.EqualError(t, err, "rows are too large")
+	}
+	// illegal case 5 (too large topmergin)
+	{
+		columns := struct {
+			No []int
+		}{}
+		err := LoadColumns(strings.NewReader(csv), 4, 10, &columns)
+
+		assert.EqualError(t, err, "topmergin is too large")
+	}
+}
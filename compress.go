@@ -0,0 +1,114 @@
+package gotinycsv
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+)
+
+// Compression is the compression format of the csv data.
+type Compression int
+
+const (
+	// CompressionAuto decompresses gzip and bzip2 data detected by the magic bytes, and reads the other data as it is.
+	CompressionAuto Compression = iota
+	// CompressionNone reads the data as it is.
+	CompressionNone
+	// Gzip decompresses gzip data by compress/gzip. Concatenated gzip members are read as one stream.
+	Gzip
+	// Bzip2 decompresses bzip2 data by compress/bzip2.
+	Bzip2
+)
+
+// DefaultCompression is the compression format of a load, which is fixed at the start of each call.
+var DefaultCompression = CompressionAuto
+
+var (
+	gzipMagic  = []byte{0x1F, 0x8B, 0x08}
+	bzip2Magic = []byte("BZh")
+	// the magic numbers of the first block and the end of an empty stream, which follow the block size
+	bzip2BlockMagic = []byte{0x31, 0x41, 0x59, 0x26, 0x53, 0x59}
+	bzip2EndMagic   = []byte{0x17, 0x72, 0x45, 0x38, 0x50, 0x90}
+)
+
+// bzip2HeaderSize is the number of bytes inspected to detect bzip2 data.
+const bzip2HeaderSize = 10
+
+func (c Compression) String() string {
+	switch c {
+	case CompressionAuto:
+		return "auto"
+	case CompressionNone:
+		return "none"
+	case Gzip:
+		return "gzip"
+	case Bzip2:
+		return "bzip2"
+	}
+	return fmt.Sprintf("Compression(%d)", int(c))
+}
+
+// DecompressError is the error of corrupt compressed data, which is distinguished from csv parse errors.
+type DecompressError struct {
+	Compression Compression
+	Err         error
+}
+
+func (e *DecompressError) Error() string {
+	return fmt.Sprintf("corrupt %s data: %v", e.Compression, e.Err)
+}
+
+func (e *DecompressError) Unwrap() error {
+	return e.Err
+}
+
+// detectCompression returns the compression format detected by the magic bytes at the start of "head".
+func detectCompression(head []byte) Compression {
+	switch {
+	case bytes.HasPrefix(head, gzipMagic):
+		return Gzip
+	case len(head) >= bzip2HeaderSize && bytes.HasPrefix(head, bzip2Magic) && '1' <= head[3] && head[3] <= '9' &&
+		(bytes.Equal(head[4:10], bzip2BlockMagic) || bytes.Equal(head[4:10], bzip2EndMagic)):
+		return Bzip2
+	}
+	return CompressionNone
+}
+
+// decompress returns the reader of the data of "r" decompressed by "c".
+// CompressionAuto is resolved by the magic bytes at the start of "r".
+func decompress(r io.Reader, c Compression) (io.Reader, error) {
+	if c == CompressionAuto {
+		br := bufio.NewReader(r)
+		// a short read leaves the error to the next read
+		head, _ := br.Peek(bzip2HeaderSize)
+		c, r = detectCompression(head), br
+	}
+	switch c {
+	case Gzip:
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, &DecompressError{Compression: Gzip, Err: err}
+		}
+		return &decompressReader{r: zr, c: Gzip}, nil
+	case Bzip2:
+		return &decompressReader{r: bzip2.NewReader(r), c: Bzip2}, nil
+	}
+	return r, nil
+}
+
+// decompressReader reports the errors of the decompressor as *DecompressError.
+type decompressReader struct {
+	r io.Reader
+	c Compression
+}
+
+func (d *decompressReader) Read(p []byte) (int, error) {
+	n, err := d.r.Read(p)
+	if err != nil && err != io.EOF {
+		err = &DecompressError{Compression: d.c, Err: err}
+	}
+	return n, err
+}
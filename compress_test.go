@@ -0,0 +1,113 @@
+package gotinycsv
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// bzip2CSV is "Name,Age\nAlice,30\nBob,25\n" compressed by bzip2.
+var bzip2CSV = []byte{
+	0x42, 0x5a, 0x68, 0x39, 0x31, 0x41, 0x59, 0x26, 0x53, 0x59, 0x21, 0xf6,
+	0x51, 0x0b, 0x00, 0x00, 0x06, 0xdd, 0x00, 0x00, 0x10, 0x00, 0x04, 0x5a,
+	0x00, 0x30, 0x01, 0x3a, 0xa6, 0xa0, 0x00, 0x22, 0x26, 0x9a, 0x36, 0xa0,
+	0x69, 0xa7, 0xa8, 0x53, 0x4c, 0x8c, 0x4c, 0x4c, 0x4e, 0x33, 0xed, 0x90,
+	0x3a, 0x05, 0x14, 0xd1, 0x99, 0x93, 0xd2, 0xa4, 0xfc, 0x5d, 0xc9, 0x14,
+	0xe1, 0x42, 0x40, 0x87, 0xd9, 0x44, 0x2c,
+}
+
+func gzipString(t *testing.T, s string) []byte {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := zw.Write([]byte(s))
+	assert.NoError(t, err)
+	assert.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func Test_Load_compression(t *testing.T) {
+	type csventry struct {
+		Name string
+		Age  int
+	}
+	csv := "Name,Age\nAlice,30\nBob,25\n"
+	gz := gzipString(t, csv)
+	expected := []csventry{{"Alice", 30}, {"Bob", 25}}
+
+	// normal case 1 (detected by the magic bytes)
+	{
+		for _, data := range [][]byte{gz, bzip2CSV, []byte(csv)} {
+			entries := []csventry{}
+			assert.NoError(t, Load(bytes.NewReader(data), 1, 10, &entries))
+			assert.Equal(t, expected, entries)
+
+			entries = []csventry{}
+			assert.NoError(t, LoadReaderAt(bytes.NewReader(data), int64(len(data)), 1, 10, 2, &entries))
+			assert.Equal(t, expected, entries)
+
+			d := NewDecoder(bytes.NewReader(data), 1)
+			var e csventry
+			assert.True(t, d.Next())
+			assert.NoError(t, d.Decode(&e))
+			assert.Equal(t, expected[0], e)
+		}
+	}
+	// normal case 2 (vertically, with a byte order mark and options)
+	{
+		entries := []csventry{}
+		assert.NoError(t, LoadVertically(bytes.NewReader(gzipString(t, "\ufeffName,Alice,Bob\nAge,30,25\n")), 0, 1, 10, &entries))
+		assert.Equal(t, expected, entries)
+
+		entries, err := LoadAsWith[csventry](bytes.NewReader(bzip2CSV), WithCompression(Bzip2), WithAuto(0))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, entries)
+
+		// concatenated gzip members are one stream
+		multi := append(gzipString(t, "Name,Age\nAlice,30\n"), gzipString(t, "Bob,25\n")...)
+		entries, err = LoadAsWith[csventry](bytes.NewReader(multi), WithSkipRows(1), WithCompression(Gzip))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, entries)
+	}
+	// normal case 3 (not decompressed)
+	{
+		entries := []csventry{}
+		assert.NoError(t, LoadWith(strings.NewReader("BZh,1\nBZh9,2\n"), &entries))
+		assert.Equal(t, []csventry{{"BZh", 1}, {"BZh9", 2}}, entries)
+
+		err := LoadWith(bytes.NewReader(gz), &entries, WithSkipRows(1), WithCompression(CompressionNone))
+		assert.Error(t, err)
+		var de *DecompressError
+		assert.False(t, errors.As(err, &de))
+	}
+	// illegal case 1 (corrupt gzip data)
+	{
+		corrupt := append([]byte{}, gz...)
+		corrupt[len(corrupt)-5] ^= 0xFF // checksum
+		truncated := gz[:len(gz)/2]
+		for _, data := range [][]byte{corrupt, truncated} {
+			entries := []csventry{}
+			err := Load(bytes.NewReader(data), 1, 10, &entries)
+			var de *DecompressError
+			assert.True(t, errors.As(err, &de))
+			assert.Equal(t, Gzip, de.Compression)
+		}
+		entries := []csventry{}
+		err := LoadWith(strings.NewReader(csv), &entries, WithCompression(Gzip))
+		assert.ErrorIs(t, err, gzip.ErrHeader)
+		assert.EqualError(t, err, "corrupt gzip data: gzip: invalid header")
+	}
+	// illegal case 2 (corrupt bzip2 data)
+	{
+		corrupt := append([]byte{}, bzip2CSV...)
+		corrupt[20] ^= 0xFF
+		entries := []csventry{}
+		err := LoadReaderAt(bytes.NewReader(corrupt), int64(len(corrupt)), 1, 10, 2, &entries)
+		var de *DecompressError
+		assert.True(t, errors.As(err, &de))
+		assert.Equal(t, Bzip2, de.Compression)
+	}
+}
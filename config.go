@@ -0,0 +1,88 @@
+package gotinycsv
+
+// config is the settings of a load, built from the arguments of the public functions or options.
+type config struct {
+	topmergin    int
+	hasTopmergin bool
+	maxrows      int
+	leftmergin   int
+	maxcols      int
+	workers      int
+	timelayout   string
+	unexported   UnexportedPolicy
+	// hasUnexported is true if the policy is given by WithUnexported
+	hasUnexported bool
+	// traditional is true for Load and LoadVertically, which follow DefaultUnexported as it is
+	traditional bool
+	columns     ColumnPolicy
+	dialect     Dialect
+	// hasEncoding is true if the encoding is given by WithEncoding, even if it is UTF8
+	hasEncoding bool
+	auto        bool
+	sniffSize   int
+	compression Compression
+	widths      []int
+	widthUnit   WidthUnit
+	trim        TrimPolicy
+	dup         DuplicateKeyPolicy
+	clone       bool
+	intern      bool
+	internLimit int
+	// interns is created for each call of newConfig, so that it is not shared by a Config
+	interns *internTables
+}
+
+// newConfig returns the settings of "opts" applied to the defaults.
+// The package defaults are copied at the call, so that a load is not affected by changes of them during it.
+// Unless the policy for unexported fields is given by WithUnexported, only Load and LoadVertically follow DefaultUnexported,
+// and the other functions are in safe mode.
+func newConfig(opts ...Option) *config {
+	cfg := &config{
+		timelayout:  options(nil).timeLayout(),
+		unexported:  DefaultUnexported,
+		columns:     DefaultColumns,
+		compression: DefaultCompression,
+		trim:        DefaultTrim,
+		dup:         DefaultDuplicateKey,
+		clone:       CloneStrings,
+		intern:      InternStrings,
+		internLimit: InternLimit,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if !cfg.traditional && !cfg.hasUnexported && cfg.unexported == UnexportedSet {
+		cfg.unexported = UnexportedError
+	}
+	cfg.interns = &internTables{}
+	return cfg
+}
+
+// safe returns a copy of "cfg" for the generic functions, which never write unexported fields.
+// The policy for unexported fields is followed only if it is UnexportedSkip or UnexportedError.
+func (cfg *config) safe() *config {
+	c := *cfg
+	if c.unexported == UnexportedSet {
+		c.unexported = UnexportedError
+	}
+	return &c
+}
+
+// explicitEncoding reports whether the encoding is given, so that it must not be detected.
+func (cfg *config) explicitEncoding() bool {
+	return cfg.hasEncoding || cfg.dialect.Encoding != UTF8
+}
+
+// withTraditional marks the settings of Load and LoadVertically, which write unexported fields by default.
+func withTraditional() Option {
+	return func(cfg *config) {
+		cfg.traditional = true
+	}
+}
+
+// withOps returns the option of the time-layout given by "ops" of the traditional functions.
+func withOps(ops []string) Option {
+	return func(cfg *config) {
+		cfg.timelayout = options(ops).timeLayout()
+	}
+}
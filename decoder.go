@@ -0,0 +1,179 @@
+package gotinycsv
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// Position is the position of a record in the csv data.
+type Position struct {
+	// Row is the 0-based index of the record excluding the top margin.
+	Row int
+	// Line is the 1-based line number where the record starts.
+	Line int
+}
+
+// Decoder reads a CSV record by record and converts it into a struct.
+// Unlike Load, it never buffers the whole csv data, so the memory usage is constant regardless of the size of the csv data.
+//
+// The record buffer is reused, and cells are converted immediately into the struct.
+// Decoding a row into a struct costs at most one allocation (the string backing the record)
+// plus one per non-empty string field cloned by CloneStrings or the "clone" tag option.
+// Note that string fields which are not cloned share the backing string of the whole record.
+//
+//	dec := gotinycsv.NewDecoder(r, topmergin)
+//	for dec.Next() {
+//		var row Entry
+//		if err := dec.Decode(&row); err != nil {
+//			return err
+//		}
+//	}
+//	if err := dec.Err(); err != nil {
+//		return err
+//	}
+type Decoder struct {
+	cr        *csv.Reader
+	topmergin int
+	cfg       *config
+	header    []string
+	record    []string
+	rows      int
+	pos       Position
+	err       error
+}
+
+// NewDecoder returns a Decoder reading from "r".
+// Skip the "topmergin" lines from the top line, the last of them is the header used for map[string]string.
+// The first element of "ops" is time-layout.
+// Unexported fields are handled in safe mode (see DefaultUnexported).
+func NewDecoder(r io.Reader, topmergin int, ops ...string) *Decoder {
+	return NewDecoderWith(r, WithSkipRows(topmergin), withOps(ops))
+}
+
+// NewDecoderWith returns a Decoder reading from "r" with options.
+// It is the same as NewDecoder except that the settings are given by "opts".
+func NewDecoderWith(r io.Reader, opts ...Option) *Decoder {
+	return newDecoder(r, newConfig(opts...))
+}
+
+func newDecoder(r io.Reader, cfg *config) *Decoder {
+	d := &Decoder{}
+	if r == nil {
+		d.err = fmt.Errorf("reader is nil")
+		return d
+	}
+	r, cfg, err := cfg.input(r, true)
+	if err != nil {
+		d.err = err
+		return d
+	}
+	d.topmergin, d.cfg = cfg.topmergin, cfg
+	d.cr = cfg.newReader(r)
+	// records are converted before the next read, so the record buffer can be reused
+	d.cr.ReuseRecord = true
+	return d
+}
+
+// Next reads the next record and reports whether it exists.
+// It returns false at the end of the csv data or on an error, which is reported by Err.
+func (d *Decoder) Next() bool {
+	if d.err != nil {
+		return false
+	}
+	for ; d.rows < d.topmergin; d.rows++ {
+		record, err := d.cr.Read()
+		if err == io.EOF {
+			d.err = fmt.Errorf("topmergin is too large")
+			return false
+		}
+		d.header = append(d.header[:0], record...)
+	}
+	record, err := d.cr.Read()
+	if err == io.EOF {
+		d.record = nil
+		return false
+	}
+	if err != nil {
+		d.err = err
+		return false
+	}
+	d.rows++
+	d.record = record
+	line, _ := d.cr.FieldPos(0)
+	d.pos = Position{Row: d.rows - d.topmergin - 1, Line: line}
+	return true
+}
+
+// Position returns the position of the current record.
+func (d *Decoder) Position() Position {
+	return d.pos
+}
+
+// Err returns the first error that occurred in Next, except for io.EOF.
+func (d *Decoder) Err() error {
+	return d.err
+}
+
+// Header returns the last line of the top margin.
+func (d *Decoder) Header() []string {
+	return d.header
+}
+
+// Decode converts the current record into "out".
+// "out" is a pointer to struct, a pointer to pointer to struct (allocated if nil) or a pointer to map[string]string keyed by the header.
+// This function does not emit an error if the conversion from a csv field to a structure field fails,
+// except for unknown labels of the types registered by RegisterEnum.
+func (d *Decoder) Decode(out interface{}) error {
+	if d.record == nil {
+		return fmt.Errorf("no record to decode (did you forget Next?)")
+	}
+	ref := reflect.ValueOf(out)
+	if ref.Kind() != reflect.Ptr || ref.IsNil() {
+		return fmt.Errorf("failed to obtain a reference to out (did you forget &?)")
+	}
+	ref = ref.Elem()
+	if ref.Kind() == reflect.Ptr {
+		if ref.IsNil() {
+			ref.Set(reflect.New(ref.Type().Elem()))
+		}
+		ref = ref.Elem()
+	}
+
+	if ref.Type() == stringMapType {
+		return d.decodeMap(ref)
+	}
+	if ref.Kind() != reflect.Struct {
+		return fmt.Errorf("out reference does not point to a struct")
+	}
+
+	plan, err := checkedPlan(ref.Type(), d.cfg)
+	if err != nil {
+		return err
+	}
+	record, err := fitRecord(d.cfg.columns, d.cr, d.record, len(plan.columns), plan.explicit)
+	if err != nil {
+		return err
+	}
+	return plan.setRecord(ref, record, d.cfg)
+}
+
+func (d *Decoder) decodeMap(ref reflect.Value) error {
+	if d.header == nil {
+		return fmt.Errorf("header is required for elements of map (topmergin must be 1 or more)")
+	}
+	record, err := fitRecord(d.cfg.columns, d.cr, d.record, len(d.header), false)
+	if err != nil {
+		return err
+	}
+	if len(d.header) < len(record) {
+		return fmt.Errorf("number of fields in the header may not match the number of fields in the CSV.")
+	}
+	m := make(map[string]string, len(record))
+	for cols, v := range record {
+		m[d.cfg.trim.apply(d.header[cols])] = d.cfg.trim.apply(v)
+	}
+	ref.Set(reflect.ValueOf(m))
+	return nil
+}
@@ -0,0 +1,169 @@
+package gotinycsv
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Decoder(t *testing.T) {
+	csv := `No,Name,Birth
+1,Alex,1999.01.01
+2,Bert,2001.02.02
+3,Carl,2002.05.05
+`
+	// normal case 1 (struct)
+	{
+		type csventry struct {
+			No    int
+			Name  string
+			Birth time.Time
+		}
+
+		entries := []csventry{}
+		dec := NewDecoder(strings.NewReader(csv), 1)
+		for dec.Next() {
+			var entry csventry
+			assert.NoError(t, dec.Decode(&entry))
+			entries = append(entries, entry)
+		}
+
+		assert.NoError(t, dec.Err())
+		assert.Equal(t, []string{"No", "Name", "Birth"}, dec.Header())
+		assert.Equal(t, 3, len(entries))
+		assert.Equal(t, 3, entries[2].No)
+		assert.Equal(t, "Bert", entries[1].Name)
+		assert.Equal(t, "1999-01-01 00:00:00 +0000 UTC", entries[0].Birth.String())
+	}
+	// normal case 2 (map and time-layout)
+	{
+		csv := `No,Name,Birth
+1,Alex,1999-01-01
+`
+		dec := NewDecoder(strings.NewReader(csv), 1, "2006-01-02")
+		assert.True(t, dec.Next())
+		var m map[string]string
+		assert.NoError(t, dec.Decode(&m))
+		assert.Equal(t, map[string]string{"No": "1", "Name": "Alex", "Birth": "1999-01-01"}, m)
+		var entry struct {
+			No    int
+			Name  string
+			Birth time.Time
+		}
+		assert.NoError(t, dec.Decode(&entry))
+		assert.Equal(t, "1999-01-01 00:00:00 +0000 UTC", entry.Birth.String())
+		assert.False(t, dec.Next())
+		assert.NoError(t, dec.Err())
+	}
+	// illegal case 1 (collapse format)
+	{
+		csv := `1,Alex
+2,Bert,extra
+`
+		dec := NewDecoder(strings.NewReader(csv), 0)
+		assert.True(t, dec.Next())
+		assert.False(t, dec.Next())
+		assert.EqualError(t, dec.Err(), "record on line 2: wrong number of fields")
+	}
+	// illegal case 2 (too large topmergin)
+	{
+		dec := NewDecoder(strings.NewReader(csv), 10)
+		assert.False(t, dec.Next())
+		assert.EqualError(t, dec.Err(), "topmergin is too large")
+	}
+	// illegal case 3 (Decode before Next)
+	{
+		var entry struct{}
+		dec := NewDecoder(strings.NewReader(csv), 1)
+		assert.EqualError(t, dec.Decode(&entry), "no record to decode (did you forget Next?)")
+	}
+	// illegal case 4 (struct fields less than CSV fields)
+	{
+		var entry struct {
+			No int
+		}
+		dec := NewDecoder(strings.NewReader(csv), 1)
+		assert.True(t, dec.Next())
+		assert.EqualError(t, dec.Decode(&entry), "number of fields in the defined structure may not match the number of fields in the CSV.")
+		assert.EqualError(t, dec.Decode(entry), "failed to obtain a reference to out (did you forget &?)")
+	}
+	// illegal case 5 (io.Reader is nil)
+	{
+		dec := NewDecoder(nil, 0)
+		assert.False(t, dec.Next())
+		assert.EqualError(t, dec.Err(), "reader is nil")
+	}
+}
+
+func Test_Decoder_allocs(t *testing.T) {
+	var sb strings.Builder
+	for i := 0; i < 10000; i++ {
+		sb.WriteString("1,Alex,41.5,1999.01.01,JP\n")
+	}
+	csv := sb.String()
+
+	// normal case 1 (at most one allocation per row)
+	{
+		var entry struct {
+			No      int
+			Name    string
+			Height  float64
+			Birth   time.Time
+			Country string
+		}
+		dec := NewDecoder(strings.NewReader(csv), 0)
+		dec.Next()
+		assert.NoError(t, dec.Decode(&entry))
+
+		allocs := testing.AllocsPerRun(1000, func() {
+			dec.Next()
+			dec.Decode(&entry)
+		})
+
+		assert.LessOrEqual(t, allocs, 1.0)
+		assert.Equal(t, "JP", entry.Country)
+	}
+	// normal case 2 (cloned string fields cost one more allocation each)
+	{
+		var entry struct {
+			No      int
+			Name    string `csv:",clone"`
+			Height  float64
+			Birth   time.Time
+			Country string
+		}
+		dec := NewDecoder(strings.NewReader(csv), 0)
+		dec.Next()
+		assert.NoError(t, dec.Decode(&entry))
+
+		allocs := testing.AllocsPerRun(1000, func() {
+			dec.Next()
+			dec.Decode(&entry)
+		})
+
+		assert.LessOrEqual(t, allocs, 2.0)
+		assert.Equal(t, "Alex", entry.Name)
+	}
+	// normal case 3 (ForEach)
+	{
+		rows := 0
+		allocs := testing.AllocsPerRun(1, func() {
+			rows = 0
+			ForEach(strings.NewReader(csv), 0, func(row *struct {
+				No      int
+				Name    string
+				Height  float64
+				Birth   time.Time
+				Country string
+			}, pos Position) error {
+				rows++
+				return nil
+			})
+		})
+
+		assert.Equal(t, 10000, rows)
+		assert.Less(t, allocs/10000, 1.01)
+	}
+}
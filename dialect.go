@@ -0,0 +1,79 @@
+package gotinycsv
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Dialect is the format of a CSV.
+// Its methods are the same as the functions of the package, except that "ops" is replaced by the dialect.
+// The zero value is the format of encoding/csv with the time-layout "2006.1.2".
+//
+//	tsv := gotinycsv.Dialect{Comma: '\t', Comment: '#', TimeLayout: "2006-01-02"}
+//	err := tsv.Load(r, topmergin, maxrows, &out)
+type Dialect struct {
+	// Comma is the field delimiter. ',' is used if it is 0.
+	Comma rune
+	// Comment is the character starting a comment line. Comments are not allowed if it is 0.
+	Comment rune
+	// LazyQuotes allows a quote in an unquoted field and a non-doubled quote in a quoted field.
+	LazyQuotes bool
+	// TrimLeadingSpace ignores leading white spaces of a field, even if it is quoted.
+	TrimLeadingSpace bool
+	// FieldsPerRecord is the same as csv.Reader. If it is 0, DefaultColumns is followed.
+	FieldsPerRecord int
+	// TimeLayout is the layout of time.Time fields. "2006.1.2" is used if it is empty.
+	TimeLayout string
+	// Encoding is the character encoding. A byte order mark takes precedence over it.
+	Encoding Encoding
+}
+
+// chunkable reports whether the csv data can be split by splitChunks,
+// which only tracks quotes and line breaks.
+func (d Dialect) chunkable() bool {
+	return d.Comment == 0 && !d.LazyQuotes
+}
+
+// Load is the same as the function Load.
+func (d Dialect) Load(r io.Reader, topmergin int, maxrows int, out interface{}) error {
+	return LoadWith(r, out, WithDialect(d), WithSkipRows(topmergin), WithMaxRows(maxrows), withTraditional())
+}
+
+// LoadVertically is the same as the function LoadVertically.
+func (d Dialect) LoadVertically(r io.Reader, topmergin int, leftmergin int, maxcols int, out interface{}) error {
+	if r == nil {
+		return fmt.Errorf("reader is nil")
+	}
+	if maxcols == 0 {
+		return fmt.Errorf("maxcols is 0")
+	}
+	return LoadVerticallyWith(r, out, WithDialect(d), WithSkipRows(topmergin), WithSkipColumns(leftmergin), WithMaxColumns(maxcols), withTraditional())
+}
+
+// LoadColumns is the same as the function LoadColumns.
+func (d Dialect) LoadColumns(r io.Reader, topmergin int, maxrows int, out interface{}) error {
+	return LoadColumnsWith(r, out, WithDialect(d), WithSkipRows(topmergin), WithMaxRows(maxrows))
+}
+
+// LoadParallel is the same as the function LoadParallel.
+func (d Dialect) LoadParallel(r io.Reader, topmergin int, maxrows int, workers int, out interface{}) error {
+	return LoadParallelWith(r, out, WithDialect(d), WithSkipRows(topmergin), WithMaxRows(maxrows), WithWorkers(workers))
+}
+
+// LoadReaderAt is the same as the function LoadReaderAt.
+// If comments or lazy quotes are allowed, the data is read sequentially as LoadParallel,
+// because record boundaries cannot be found without parsing from the top.
+func (d Dialect) LoadReaderAt(ra io.ReaderAt, size int64, topmergin int, maxrows int, workers int, out interface{}) error {
+	return LoadReaderAtWith(ra, size, out, WithDialect(d), WithSkipRows(topmergin), WithMaxRows(maxrows), WithWorkers(workers))
+}
+
+// LoadFile is the same as the function LoadFile.
+func (d Dialect) LoadFile(f *os.File, topmergin int, maxrows int, workers int, out interface{}) error {
+	return LoadFileWith(f, out, WithDialect(d), WithSkipRows(topmergin), WithMaxRows(maxrows), WithWorkers(workers))
+}
+
+// NewDecoder is the same as the function NewDecoder.
+func (d Dialect) NewDecoder(r io.Reader, topmergin int) *Decoder {
+	return NewDecoderWith(r, WithDialect(d), WithSkipRows(topmergin))
+}
@@ -0,0 +1,107 @@
+package gotinycsv
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Dialect(t *testing.T) {
+	type csventry struct {
+		No    int
+		Name  string
+		Birth time.Time
+	}
+
+	// normal case 1 (TSV with comments)
+	{
+		tsv := `No	Name	Birth
+# comment line
+1	Alex	1999-01-01
+2	"Bert ""B"""	2001-02-02
+`
+		d := Dialect{Comma: '\t', Comment: '#', TimeLayout: "2006-01-02"}
+		entries := []csventry{}
+		assert.NoError(t, d.Load(strings.NewReader(tsv), 1, 10, &entries))
+		assert.Equal(t, 2, len(entries))
+		assert.Equal(t, `Bert "B"`, entries[1].Name)
+		assert.Equal(t, "2001-02-02 00:00:00 +0000 UTC", entries[1].Birth.String())
+
+		chunked := []csventry{}
+		assert.NoError(t, d.LoadReaderAt(strings.NewReader(tsv), int64(len(tsv)), 1, 10, 2, &chunked))
+		assert.Equal(t, entries, chunked)
+
+		dec := d.NewDecoder(strings.NewReader(tsv), 1)
+		assert.True(t, dec.Next())
+		assert.Equal(t, []string{"No", "Name", "Birth"}, dec.Header())
+	}
+	// normal case 2 (semicolon, lazy quotes and leading spaces)
+	{
+		csv := `1; Alex "A";1999.1.1
+2; Bert;2001.2.2
+`
+		d := Dialect{Comma: ';', LazyQuotes: true, TrimLeadingSpace: true}
+		entries := []csventry{}
+		assert.NoError(t, d.LoadParallel(strings.NewReader(csv), 0, 10, 2, &entries))
+		assert.Equal(t, `Alex "A"`, entries[0].Name)
+		assert.Equal(t, "2001-02-02 00:00:00 +0000 UTC", entries[1].Birth.String())
+
+		var columns struct {
+			No []int
+			_  []string
+			_  []string
+		}
+		assert.NoError(t, d.LoadColumns(strings.NewReader(csv), 0, 10, &columns))
+		assert.Equal(t, []int{1, 2}, columns.No)
+	}
+	// normal case 3 (LoadVertically)
+	{
+		csv := `Name|Alex|Bert
+No|1|2
+`
+		type csventry struct {
+			Name string
+			No   int
+		}
+
+		entries := []csventry{}
+		assert.NoError(t, Dialect{Comma: '|'}.LoadVertically(strings.NewReader(csv), 0, 1, 10, &entries))
+		assert.Equal(t, []csventry{{"Alex", 1}, {"Bert", 2}}, entries)
+	}
+	// illegal case 1 (FieldsPerRecord)
+	{
+		csv := `1,Alex,1999.1.1
+2,Bert,2001.2.2
+`
+		entries := []csventry{}
+		err := Dialect{FieldsPerRecord: 2}.Load(strings.NewReader(csv), 0, 10, &entries)
+		assert.EqualError(t, err, "record on line 1: wrong number of fields")
+	}
+	// illegal case 2 (invalid delimiter)
+	{
+		entries := []csventry{}
+		err := Dialect{Comma: '"'}.Load(strings.NewReader("1\n"), 0, 10, &entries)
+		assert.EqualError(t, err, "csv: invalid field or comment delimiter")
+	}
+	// illegal case 3 (ragged records of LoadVertically with FieldsPerRecord -1)
+	{
+		type csventry struct {
+			Label string
+			Age   int
+		}
+
+		entries := []csventry{}
+		err := LoadVerticallyWith(strings.NewReader("label,a,b\nage,1,2,3\n"), &entries, WithSkipColumns(1), WithFieldsPerRecord(-1))
+		assert.EqualError(t, err, "parse error on line 2, column 9: extra columns (4 of 3)")
+		assert.ErrorIs(t, err, ErrExtraColumns)
+
+		err = Dialect{FieldsPerRecord: -1}.LoadVertically(strings.NewReader("label,a,b\nage,1,2,3\n"), 0, 1, 10, &entries)
+		assert.ErrorIs(t, err, ErrExtraColumns)
+
+		err = Dialect{FieldsPerRecord: -1}.LoadVertically(strings.NewReader("label,a,b\nage\n"), 0, 2, 10, &entries)
+		assert.EqualError(t, err, "parse error on line 2, column 1: missing columns (1 of 3)")
+		assert.ErrorIs(t, err, ErrMissingColumns)
+	}
+}
@@ -0,0 +1,170 @@
+package gotinycsv
+
+import (
+	"bytes"
+	"io"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	xunicode "golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// Encoding is the character encoding of a CSV.
+// The data is decoded into UTF-8 before tokenizing, and invalid bytes are replaced by U+FFFD.
+type Encoding int
+
+const (
+	// UTF8 is UTF-8, which is not transcoded.
+	UTF8 Encoding = iota
+	// ShiftJIS is Shift_JIS including the extensions of CP932 (Windows-31J).
+	ShiftJIS
+	// EUCJP is EUC-JP.
+	EUCJP
+	// UTF16LE is UTF-16 little endian without a byte order mark.
+	UTF16LE
+	// UTF16BE is UTF-16 big endian without a byte order mark.
+	UTF16BE
+	// Latin1 is ISO-8859-1.
+	Latin1
+	// Windows1252 is Windows-1252, a superset of ISO-8859-1 used by Excel in western Europe.
+	Windows1252
+)
+
+func (e Encoding) String() string {
+	switch e {
+	case UTF8:
+		return "UTF-8"
+	case ShiftJIS:
+		return "Shift_JIS"
+	case EUCJP:
+		return "EUC-JP"
+	case UTF16LE:
+		return "UTF-16LE"
+	case UTF16BE:
+		return "UTF-16BE"
+	case Latin1:
+		return "ISO-8859-1"
+	case Windows1252:
+		return "Windows-1252"
+	}
+	return "unknown"
+}
+
+func (e Encoding) encoding() encoding.Encoding {
+	switch e {
+	case ShiftJIS:
+		return japanese.ShiftJIS
+	case EUCJP:
+		return japanese.EUCJP
+	case UTF16LE:
+		return xunicode.UTF16(xunicode.LittleEndian, xunicode.IgnoreBOM)
+	case UTF16BE:
+		return xunicode.UTF16(xunicode.BigEndian, xunicode.IgnoreBOM)
+	case Latin1:
+		return charmap.ISO8859_1
+	case Windows1252:
+		return charmap.Windows1252
+	}
+	return encoding.Nop
+}
+
+// charLen returns the number of bytes of the character at the start of "s" in the encoding.
+// An incomplete character at the end of "s" is counted by byte.
+func (e Encoding) charLen(s string) int {
+	b := s[0]
+	switch e {
+	case UTF8:
+		_, n := utf8.DecodeRuneInString(s)
+		return n
+	case ShiftJIS:
+		if (0x81 <= b && b <= 0x9F || 0xE0 <= b && b <= 0xFC) && len(s) >= 2 {
+			return 2
+		}
+	case EUCJP:
+		switch {
+		case b == 0x8F && len(s) >= 3:
+			return 3
+		case (b == 0x8E || 0xA1 <= b && b <= 0xFE) && len(s) >= 2:
+			return 2
+		}
+	}
+	return 1
+}
+
+// decodeString decodes "s" from the encoding into UTF-8.
+func (e Encoding) decodeString(s string) (string, error) {
+	if e == UTF8 {
+		return s, nil
+	}
+	return e.encoding().NewDecoder().String(s)
+}
+
+// NewReader returns a reader decoding "r" from the encoding into UTF-8.
+func (e Encoding) NewReader(r io.Reader) io.Reader {
+	if e == UTF8 {
+		return r
+	}
+	return transform.NewReader(r, e.encoding().NewDecoder())
+}
+
+// NewWriter returns a writer encoding UTF-8 into the encoding to "w", for writing back a CSV in its original encoding.
+// A character which cannot be represented in the encoding is an error.
+// Close flushes the buffered data, and does not close "w".
+//
+//	ew := gotinycsv.ShiftJIS.NewWriter(f)
+//	cw := csv.NewWriter(ew)
+//	cw.WriteAll(records)
+//	ew.Close()
+func (e Encoding) NewWriter(w io.Writer) io.WriteCloser {
+	return transform.NewWriter(w, e.encoding().NewEncoder())
+}
+
+// sniffEncoding detects the encoding of "data".
+// UTF-16 is chosen if "data" has many NUL bytes, and UTF-8 if it is valid.
+// Otherwise, the Japanese encoding decoding "data" without errors into the most Japanese characters is chosen,
+// and Windows-1252 if no Japanese characters are found.
+func sniffEncoding(data []byte) Encoding {
+	// NUL bytes are valid UTF-8, so UTF-16 is detected first
+	even, odd := 0, 0
+	for i, b := range data {
+		if b == 0 {
+			if i%2 == 0 {
+				even++
+			} else {
+				odd++
+			}
+		}
+	}
+	switch {
+	case odd > len(data)/4 && odd > even:
+		return UTF16LE
+	case even > len(data)/4:
+		return UTF16BE
+	}
+	if utf8.Valid(data) {
+		return UTF8
+	}
+
+	best, bestCount := Windows1252, 0
+	for _, e := range []Encoding{ShiftJIS, EUCJP} {
+		decoded, _, err := transform.Bytes(e.encoding().NewDecoder(), data)
+		if err != nil || bytes.ContainsRune(decoded, utf8.RuneError) {
+			continue
+		}
+		count := 0
+		for _, r := range string(decoded) {
+			// half-width katakana is not counted, because EUC-JP decoded as Shift_JIS looks like it
+			if unicode.In(r, unicode.Hiragana, unicode.Han) || (unicode.Is(unicode.Katakana, r) && r < 0xFF61) {
+				count++
+			}
+		}
+		if count > bestCount {
+			best, bestCount = e, count
+		}
+	}
+	return best
+}
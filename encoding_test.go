@@ -0,0 +1,108 @@
+package gotinycsv
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func encodeString(t *testing.T, e Encoding, s string) []byte {
+	var buf bytes.Buffer
+	w := e.NewWriter(&buf)
+	_, err := io.WriteString(w, s)
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func Test_Encoding(t *testing.T) {
+	// normal case 1 (round trip)
+	{
+		cases := []struct {
+			e Encoding
+			s string
+		}{
+			{UTF8, "番号,名前\n1,髙橋①\n"},
+			{ShiftJIS, "番号,名前\n1,髙橋①\n"},
+			{EUCJP, "番号,名前\n1,田中\n"},
+			{UTF16LE, "番号,名前\n1,😀\n"},
+			{UTF16BE, "番号,名前\n1,😀\n"},
+			{Latin1, "No,Nom\n1,Café\n"},
+			{Windows1252, "No,Nom\n1,Café €\n"},
+		}
+		for _, c := range cases {
+			b := encodeString(t, c.e, c.s)
+			decoded, err := io.ReadAll(c.e.NewReader(bytes.NewReader(b)))
+			assert.NoError(t, err)
+			assert.Equal(t, c.s, string(decoded), c.e.String())
+		}
+	}
+	// normal case 2 (sniffEncoding)
+	{
+		assert.Equal(t, UTF8, sniffEncoding([]byte("番号,名前\n")))
+		assert.Equal(t, ShiftJIS, sniffEncoding(encodeString(t, ShiftJIS, "番号,名前\n1,たなか\n")))
+		assert.Equal(t, EUCJP, sniffEncoding(encodeString(t, EUCJP, "番号,名前\n1,たなか\n")))
+		assert.Equal(t, UTF16LE, sniffEncoding(encodeString(t, UTF16LE, "No,Name\n")))
+		assert.Equal(t, UTF16BE, sniffEncoding(encodeString(t, UTF16BE, "No,Name\n")))
+		assert.Equal(t, Windows1252, sniffEncoding(encodeString(t, Windows1252, "No,Nom\n1,Café\n")))
+	}
+	// illegal case (a character which cannot be represented)
+	{
+		w := Latin1.NewWriter(io.Discard)
+		_, err := io.WriteString(w, "日本")
+		assert.Error(t, err)
+	}
+}
+
+func Test_Load_encoding(t *testing.T) {
+	type csventry struct {
+		No   int
+		Name string
+	}
+	csv := "番号,名前\n1,髙橋\n2,渡邊\n"
+	sjis := encodeString(t, ShiftJIS, csv)
+	expected := []csventry{{1, "髙橋"}, {2, "渡邊"}}
+
+	// normal case 1 (option and dialect)
+	{
+		entries := []csventry{}
+		assert.NoError(t, LoadWith(bytes.NewReader(sjis), &entries, WithSkipRows(1), WithEncoding(ShiftJIS)))
+		assert.Equal(t, expected, entries)
+
+		entries = []csventry{}
+		assert.NoError(t, Dialect{Encoding: ShiftJIS}.LoadReaderAt(bytes.NewReader(sjis), int64(len(sjis)), 1, 10, 2, &entries))
+		assert.Equal(t, expected, entries)
+	}
+	// normal case 2 (sniffed)
+	{
+		s, r, err := Sniff(bytes.NewReader(sjis), 0)
+		assert.NoError(t, err)
+		assert.Equal(t, ShiftJIS, s.Encoding)
+		entries := []csventry{}
+		assert.NoError(t, s.Dialect.Load(r, 1, 10, &entries))
+		assert.Equal(t, expected, entries)
+
+		entries, err = LoadAsWith[csventry](bytes.NewReader(sjis), WithAuto(0))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, entries)
+
+		entries = []csventry{}
+		assert.NoError(t, LoadReaderAtWith(bytes.NewReader(sjis), int64(len(sjis)), &entries, WithAuto(0)))
+		assert.Equal(t, expected, entries)
+	}
+	// normal case 3 (a byte order mark takes precedence)
+	{
+		entries := []csventry{}
+		assert.NoError(t, LoadWith(strings.NewReader("\ufeff"+csv), &entries, WithSkipRows(1), WithEncoding(ShiftJIS)))
+		assert.Equal(t, expected, entries)
+	}
+	// illegal case (wrong encoding makes mojibake, not an error)
+	{
+		entries := []csventry{}
+		assert.NoError(t, LoadWith(bytes.NewReader(sjis), &entries, WithSkipRows(1)))
+		assert.NotEqual(t, expected, entries)
+	}
+}
@@ -0,0 +1,95 @@
+package gotinycsv
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type enumTable struct {
+	values          map[string]reflect.Value
+	labels          map[interface{}]string
+	caseInsensitive bool
+}
+
+var enums sync.Map // reflect.Type -> *enumTable
+
+// RegisterEnum registers a label-to-value table for a named type.
+// "table" must be a map whose key type is string, such as map[string]Status.
+// Structure fields of the value type are set from the labels in csv fields,
+// and a label not found in the table is reported as a conversion error. An empty csv field leaves the zero value.
+// If "caseInsensitive" is true, labels are matched without regard to case.
+// Registering the same type again replaces the previous table.
+func RegisterEnum(table interface{}, caseInsensitive bool) error {
+	ref := reflect.ValueOf(table)
+	if ref.Kind() != reflect.Map || ref.Type().Key().Kind() != reflect.String {
+		return fmt.Errorf("enum table must be a map with string keys")
+	}
+	if !ref.Type().Elem().Comparable() {
+		return fmt.Errorf("enum values must be comparable")
+	}
+	et := &enumTable{
+		values:          make(map[string]reflect.Value, ref.Len()),
+		labels:          make(map[interface{}]string, ref.Len()),
+		caseInsensitive: caseInsensitive,
+	}
+	keys := ref.MapKeys()
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+	for _, k := range keys {
+		label := k.String()
+		v := ref.MapIndex(k)
+		if caseInsensitive {
+			label = strings.ToLower(label)
+		}
+		if _, ok := et.values[label]; ok {
+			return fmt.Errorf("duplicate enum label: %s", k.String())
+		}
+		et.values[label] = v
+		// the first label in sorted order becomes the label for writing
+		if _, ok := et.labels[v.Interface()]; !ok {
+			et.labels[v.Interface()] = k.String()
+		}
+	}
+	enums.Store(ref.Type().Elem(), et)
+	// compiled plans may have non-enum setters for the type
+	plans.Clear()
+	return nil
+}
+
+// EnumLabel returns the label registered for "v" by RegisterEnum.
+// If several labels are mapped to "v", the smallest one in lexical order is returned.
+func EnumLabel(v interface{}) (string, bool) {
+	et, ok := lookupEnum(reflect.TypeOf(v))
+	if !ok {
+		return "", false
+	}
+	label, ok := et.labels[v]
+	return label, ok
+}
+
+func lookupEnum(t reflect.Type) (*enumTable, bool) {
+	et, ok := enums.Load(t)
+	if !ok {
+		return nil, false
+	}
+	return et.(*enumTable), true
+}
+
+func (et *enumTable) set(ref reflect.Value, v string) error {
+	if v == "" {
+		ref.Set(reflect.Zero(ref.Type()))
+		return nil
+	}
+	label := v
+	if et.caseInsensitive {
+		label = strings.ToLower(label)
+	}
+	value, ok := et.values[label]
+	if !ok {
+		return fmt.Errorf("unknown label for %s: %s", ref.Type(), v)
+	}
+	ref.Set(value)
+	return nil
+}
@@ -0,0 +1,115 @@
+package gotinycsv
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testStatus int
+
+const (
+	testStatusUnknown testStatus = iota
+	testStatusActive
+	testStatusSuspended
+	testStatusClosed
+)
+
+type testColor string
+
+func Test_RegisterEnum(t *testing.T) {
+	// normal case
+	{
+		err := RegisterEnum(map[string]testColor{"r": "red", "red": "red", "g": "green"}, false)
+		assert.NoError(t, err)
+		label, ok := EnumLabel(testColor("red"))
+		assert.True(t, ok)
+		assert.Equal(t, "r", label)
+		_, ok = EnumLabel(testColor("blue"))
+		assert.False(t, ok)
+		_, ok = EnumLabel(1)
+		assert.False(t, ok)
+	}
+	// illegal case 1 (not a map)
+	{
+		err := RegisterEnum([]testStatus{}, false)
+		assert.EqualError(t, err, "enum table must be a map with string keys")
+	}
+	// illegal case 2 (duplicate labels without regard to case)
+	{
+		err := RegisterEnum(map[string]testStatus{"Active": testStatusActive, "ACTIVE": testStatusActive}, true)
+		assert.EqualError(t, err, "duplicate enum label: Active")
+	}
+	// illegal case 3 (values are not comparable)
+	{
+		err := RegisterEnum(map[string][]int{"a": {1}}, false)
+		assert.EqualError(t, err, "enum values must be comparable")
+	}
+}
+
+func Test_Load_enum(t *testing.T) {
+	err := RegisterEnum(map[string]testStatus{
+		"active":    testStatusActive,
+		"suspended": testStatusSuspended,
+		"closed":    testStatusClosed,
+	}, true)
+	assert.NoError(t, err)
+
+	// normal case 1
+	{
+		csv := `1,Active
+2,suspended
+3,
+4,CLOSED
+`
+		type csventry struct {
+			id     int
+			status testStatus
+		}
+
+		entries := []csventry{}
+		err := Load(strings.NewReader(csv), 0, 10, &entries)
+
+		assert.NoError(t, err)
+		assert.Equal(t, testStatusActive, entries[0].status)
+		assert.Equal(t, testStatusSuspended, entries[1].status)
+		assert.Equal(t, testStatusUnknown, entries[2].status)
+		assert.Equal(t, testStatusClosed, entries[3].status)
+		label, ok := EnumLabel(entries[3].status)
+		assert.True(t, ok)
+		assert.Equal(t, "closed", label)
+	}
+	// normal case 2 (LoadVertically)
+	{
+		csv := `ID,1,2
+Status,closed,active
+`
+		type csventry struct {
+			id     int
+			status testStatus
+		}
+
+		entries := []csventry{}
+		err := LoadVertically(strings.NewReader(csv), 0, 1, 10, &entries)
+
+		assert.NoError(t, err)
+		assert.Equal(t, testStatusClosed, entries[0].status)
+		assert.Equal(t, testStatusActive, entries[1].status)
+	}
+	// illegal case (unknown label)
+	{
+		csv := `1,active
+2,deleted
+`
+		type csventry struct {
+			id     int
+			status testStatus
+		}
+
+		entries := []csventry{}
+		err := Load(strings.NewReader(csv), 0, 10, &entries)
+
+		assert.EqualError(t, err, "unknown label for gotinycsv.testStatus: deleted")
+	}
+}
@@ -0,0 +1,281 @@
+package gotinycsv
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/width"
+)
+
+// WidthUnit specifies how the positions and widths of fixed-width fields are counted.
+type WidthUnit int
+
+const (
+	// WidthBytes counts bytes of the data in its encoding given by WithEncoding or the dialect,
+	// so that the widths of Shift_JIS or EUC-JP extracts are used as they are. Each field is decoded after it is cut.
+	// UTF-16 data is counted in bytes of UTF-8, since its lines cannot be split by bytes.
+	WidthBytes WidthUnit = iota
+	// WidthRunes counts characters.
+	WidthRunes
+	// WidthCells counts display cells, in which East Asian wide and full-width characters take 2 cells.
+	// East Asian ambiguous characters, such as "①" and "α", take 1 cell.
+	WidthCells
+)
+
+// fixedRange is the position of a structure field in a fixed-width line, counted in WidthUnit.
+type fixedRange struct {
+	field int
+	start int
+	end   int
+}
+
+// parseFixedWidthTag returns the position of the "fw" tag of "f".
+//
+//	`fw:"start,width"`
+func parseFixedWidthTag(f reflect.StructField) (start int, w int, ok bool, err error) {
+	tag, ok := f.Tag.Lookup("fw")
+	if !ok {
+		return 0, 0, false, nil
+	}
+	s, ws, found := strings.Cut(tag, ",")
+	start, err1 := strconv.Atoi(s)
+	w, err2 := strconv.Atoi(ws)
+	if !found || err1 != nil || err2 != nil || start < 0 || w <= 0 {
+		return 0, 0, false, fmt.Errorf("invalid fixed-width position: %s", tag)
+	}
+	return start, w, true, nil
+}
+
+// fixedRanges returns the positions of the fields of the struct "t" (or pointer to struct).
+// A field without the "fw" tag takes the width of its column given by WithWidths.
+func fixedRanges(t reflect.Type, plan *decodePlan, cfg *config) ([]fixedRange, error) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	offsets := make([]int, len(cfg.widths)+1)
+	for c, w := range cfg.widths {
+		if w <= 0 {
+			return nil, fmt.Errorf("invalid fixed-width column width: %d", w)
+		}
+		offsets[c+1] = offsets[c] + w
+	}
+	var ranges []fixedRange
+	for i := range plan.fields {
+		f := &plan.fields[i]
+		if f.spec.skip || f.skipped(cfg.unexported) {
+			continue
+		}
+		start, w, ok, err := parseFixedWidthTag(t.Field(i))
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			c := -1
+			for col, field := range plan.columns {
+				if field == i {
+					c = col
+					break
+				}
+			}
+			if c < 0 || c >= len(cfg.widths) {
+				if f.blank {
+					continue
+				}
+				return nil, fmt.Errorf("no fixed-width position for field %s", f.name)
+			}
+			start, w = offsets[c], cfg.widths[c]
+		}
+		ranges = append(ranges, fixedRange{field: i, start: start, end: start + w})
+	}
+	return ranges, nil
+}
+
+// positions returns the byte offset of each position of "line" counted in "unit", and the end of the line.
+// "line" is in "enc" for WidthBytes, otherwise in UTF-8.
+// The middle of a character is the offset after it, so the character belongs to the field of its first byte or cell.
+func positions(line string, unit WidthUnit, enc Encoding, buf []int) []int {
+	buf = buf[:0]
+	if unit == WidthBytes {
+		for i := 0; i < len(line); {
+			n := enc.charLen(line[i:])
+			buf = append(buf, i)
+			for k := 1; k < n; k++ {
+				buf = append(buf, i+n)
+			}
+			i += n
+		}
+		return append(buf, len(line))
+	}
+	for i, r := range line {
+		buf = append(buf, i)
+		if unit == WidthCells {
+			if k := width.LookupRune(r).Kind(); k == width.EastAsianWide || k == width.EastAsianFullwidth {
+				buf = append(buf, i+utf8.RuneLen(r))
+			}
+		}
+	}
+	return append(buf, len(line))
+}
+
+// cutFixed returns the part of "line" in "rg". "pos" is the positions of "line", or nil for single-byte characters.
+// The part beyond the end of the line is empty.
+func cutFixed(line string, pos []int, rg fixedRange) string {
+	if pos == nil {
+		return line[min(rg.start, len(line)):min(rg.end, len(line))]
+	}
+	last := len(pos) - 1
+	return line[pos[min(rg.start, last)]:pos[min(rg.end, last)]]
+}
+
+// Load a fixed-width text.
+// "r" is a reader of lines, in which each field is at the position given by the `fw:"start,width"` tag,
+// or the column widths given by WithWidths for the fields without the tag.
+// The fields are bound to the columns of WithWidths in the same way as the csv columns of Load.
+// The positions are counted in bytes of the data in its encoding by default, or in characters or display cells by WithWidthUnit.
+// Fields beyond the end of a short line are empty. Empty lines and comment lines of the dialect are skipped.
+// The field conversion is the same as Load except for safe mode (see DefaultUnexported),
+// and padding spaces are removed according to DefaultTrim or the "trim" tag option.
+// The other arguments are the same as Load.
+func LoadFixedWidth(r io.Reader, topmergin int, maxrows int, out interface{}, ops ...string) error {
+	return LoadFixedWidthWith(r, out, WithSkipRows(topmergin), WithMaxRows(maxrows), withOps(ops))
+}
+
+// LoadFixedWidthWith loads a fixed-width text with options.
+// It is the same as LoadFixedWidth except that the settings are given by "opts".
+//
+//	err := gotinycsv.LoadFixedWidthWith(r, &out, gotinycsv.WithWidths(10, 8, 4), gotinycsv.WithWidthUnit(gotinycsv.WidthCells))
+func LoadFixedWidthWith(r io.Reader, out interface{}, opts ...Option) error {
+	return loadFixedWidth(r, out, newConfig(opts...))
+}
+
+func loadFixedWidth(r io.Reader, out interface{}, cfg *config) error {
+	if r == nil {
+		return fmt.Errorf("reader is nil")
+	}
+	// the delimiter is not used, so WithAuto is ignored
+	r, enc, err := fixedInput(r, cfg)
+	if err != nil {
+		return err
+	}
+	refp, err := sliceRefPointer(out)
+	if err != nil {
+		return err
+	}
+	elemt := refp.Type().Elem()
+	if isMapElem(elemt) {
+		return fmt.Errorf("elements of slice must be struct")
+	}
+	plan, err := checkedPlan(elemt, cfg)
+	if err != nil {
+		return err
+	}
+	ranges, err := fixedRanges(elemt, plan, cfg)
+	if err != nil {
+		return err
+	}
+
+	lines, err := readLines(r, cfg)
+	if err != nil {
+		return err
+	}
+
+	// create "out" for all rows
+	if err = ensureSliceCapacity(*refp, len(lines)); err != nil {
+		return err
+	}
+
+	var pos []int
+	for rows, l := range lines {
+		line := l.text
+		if cfg.widthUnit != WidthBytes || !isASCII(line) {
+			pos = positions(line, cfg.widthUnit, enc, pos)
+		} else {
+			pos = nil
+		}
+		ref := elemRef(*refp, rows)
+		for _, rg := range ranges {
+			v, err := enc.decodeString(cutFixed(line, pos, rg))
+			if err != nil {
+				return err
+			}
+			if err = plan.setField(ref, rg.field, v, cfg); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// fixedInput returns the data of "r" decompressed, and the encoding of the fields cut from it.
+// For WidthBytes, the data is kept in its encoding except for UTF-16, otherwise it is decoded into UTF-8.
+// A byte order mark takes precedence over the encoding of "cfg".
+func fixedInput(r io.Reader, cfg *config) (io.Reader, Encoding, error) {
+	r, err := decompress(r, cfg.compression)
+	if err != nil {
+		return nil, UTF8, err
+	}
+	r, bom := skipBOM(r)
+	enc := cfg.dialect.Encoding
+	switch {
+	case bom:
+		return r, UTF8, nil
+	case cfg.widthUnit != WidthBytes || enc == UTF16LE || enc == UTF16BE:
+		return enc.NewReader(r), UTF8, nil
+	}
+	return r, enc, nil
+}
+
+// isASCII reports whether "s" consists of ASCII characters.
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// textLine is a line of a line-oriented format with its 1-based line number.
+type textLine struct {
+	text string
+	line int
+}
+
+// readLines reads the lines of a line-oriented format, skipping the "topmergin" lines of "cfg".
+// Empty lines and comment lines of the dialect are skipped, and are not counted as rows.
+func readLines(r io.Reader, cfg *config) ([]textLine, error) {
+	topmergin, maxrows := cfg.topmergin, cfg.maxrows
+	br := bufio.NewReader(r)
+	lines := make([]textLine, 0, maxrows)
+	rows := 0
+	for n := 1; ; n++ {
+		line, err := br.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if len(line) == 0 && err == io.EOF {
+			break
+		}
+		line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+		if line == "" || (cfg.dialect.Comment != 0 && strings.HasPrefix(line, string(cfg.dialect.Comment))) {
+			continue
+		}
+		if rows++; rows <= topmergin {
+			continue
+		}
+		if maxrows > 0 && rows > topmergin+maxrows {
+			return nil, fmt.Errorf("rows are too large")
+		}
+		lines = append(lines, textLine{text: line, line: n})
+	}
+	if rows <= topmergin {
+		return nil, fmt.Errorf("topmergin is too large")
+	}
+	return lines, nil
+}
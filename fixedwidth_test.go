@@ -0,0 +1,134 @@
+package gotinycsv
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_LoadFixedWidth(t *testing.T) {
+	type fwentry struct {
+		Code  string    `fw:"0,4"`
+		Name  string    `fw:"4,10"`
+		Age   int       `fw:"14,3"`
+		Birth time.Time `fw:"17,10"`
+		Memo  string    `csv:"-"`
+	}
+
+	// normal case 1 (byte positions)
+	{
+		text := "CODENAME      AGEBIRTH\n" +
+			"A001Alex       302000-01-02\r\n" +
+			"\n" +
+			"A002Bert       25\n"
+		entries := []fwentry{}
+		assert.NoError(t, LoadFixedWidth(strings.NewReader(text), 1, 10, &entries, "2006-01-02"))
+		assert.Equal(t, []fwentry{
+			{"A001", "Alex", 30, time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC), ""},
+			{"A002", "Bert", 25, time.Time{}, ""},
+		}, entries)
+	}
+	// normal case 2 (East Asian wide characters in display cells, and in characters)
+	{
+		type wide struct {
+			Name string `fw:"0,10"`
+			City string `fw:"10,6"`
+			Age  int    `fw:"16,3"`
+		}
+		text := "山田太郎  東京   30\n" +
+			"ｽｽﾞｷｲﾁﾛｳ  大阪   25\n"
+		entries := []wide{}
+		assert.NoError(t, LoadFixedWidthWith(strings.NewReader(text), &entries, WithWidthUnit(WidthCells)))
+		assert.Equal(t, []wide{{"山田太郎", "東京", 30}, {"ｽｽﾞｷｲﾁﾛｳ", "大阪", 25}}, entries)
+
+		type runes struct {
+			Name string `fw:"0,4"`
+			Age  int    `fw:"4,3"`
+		}
+		runeEntries := []runes{}
+		assert.NoError(t, LoadFixedWidthWith(strings.NewReader("山田太郎 30\n"), &runeEntries, WithWidthUnit(WidthRunes)))
+		assert.Equal(t, []runes{{"山田太郎", 30}}, runeEntries)
+	}
+	// normal case 3 (column widths bound like csv columns, and Shift_JIS data)
+	{
+		type columns struct {
+			Name string
+			Age  int `csv:"#2"`
+		}
+		var buf bytes.Buffer
+		w := ShiftJIS.NewWriter(&buf)
+		w.Write([]byte("# comment\n田中    東京  41\n"))
+		w.Close()
+		entries := []columns{}
+		assert.NoError(t, LoadFixedWidthWith(&buf, &entries,
+			WithWidths(8, 6, 2), WithWidthUnit(WidthCells), WithEncoding(ShiftJIS), WithComment('#')))
+		assert.Equal(t, []columns{{"田中", 41}}, entries)
+
+		// the same struct type is loaded from csv
+		entries = []columns{}
+		assert.NoError(t, Load(strings.NewReader("田中,東京,41\n"), 0, 10, &entries))
+		assert.Equal(t, []columns{{"田中", 41}}, entries)
+	}
+	// normal case 4 (a wide character across the boundary belongs to the field of its first cell)
+	{
+		type split struct {
+			A string `fw:"0,3"`
+			B string `fw:"3,3"`
+		}
+		entries := []split{}
+		assert.NoError(t, LoadFixedWidthWith(strings.NewReader("ab田cd\n"), &entries, WithWidthUnit(WidthCells)))
+		assert.Equal(t, []split{{"ab田", "cd"}}, entries)
+	}
+	// normal case 5 (bytes are counted in the encoding of the data, and never cut a character)
+	{
+		type sjis struct {
+			A string `fw:"0,4"`
+			B string `fw:"4,2"`
+		}
+		var buf bytes.Buffer
+		w := ShiftJIS.NewWriter(&buf)
+		w.Write([]byte("①②ab\nｱｲｳｴｵｶ\n"))
+		w.Close()
+		entries := []sjis{}
+		assert.NoError(t, LoadFixedWidthWith(&buf, &entries, WithEncoding(ShiftJIS)))
+		assert.Equal(t, []sjis{{"①②", "ab"}, {"ｱｲｳｴ", "ｵｶ"}}, entries)
+
+		type utf8Fields struct {
+			A string `fw:"0,2"`
+			B string `fw:"2,3"`
+		}
+		utf8Entries := []utf8Fields{}
+		assert.NoError(t, LoadFixedWidth(strings.NewReader("あいうえお\n"), 0, 10, &utf8Entries))
+		assert.Equal(t, []utf8Fields{{"あ", "い"}}, utf8Entries)
+	}
+	// illegal case 1 (positions)
+	{
+		type noPosition struct {
+			A string `fw:"0,3"`
+			B string
+		}
+		assert.EqualError(t, LoadFixedWidth(strings.NewReader("abc\n"), 0, 10, &[]noPosition{}), "no fixed-width position for field B")
+
+		type badTag struct {
+			A string `fw:"0"`
+		}
+		assert.EqualError(t, LoadFixedWidth(strings.NewReader("abc\n"), 0, 10, &[]badTag{}), "invalid fixed-width position: 0")
+
+		type widths struct {
+			A string
+		}
+		err := LoadFixedWidthWith(strings.NewReader("abc\n"), &[]widths{}, WithWidths(0))
+		assert.EqualError(t, err, "invalid fixed-width column width: 0")
+	}
+	// illegal case 2 (rows and conversion)
+	{
+		entries := []fwentry{}
+		assert.EqualError(t, LoadFixedWidth(strings.NewReader("A001Alex       30\n"), 1, 10, &entries), "topmergin is too large")
+		assert.EqualError(t, LoadFixedWidth(strings.NewReader("A001Alex 30\nA002Bert 25\n"), 0, 1, &entries), "rows are too large")
+		assert.Error(t, LoadFixedWidth(nil, 0, 10, &entries))
+		assert.Error(t, LoadFixedWidth(strings.NewReader("A001\n"), 0, 10, entries))
+	}
+}
@@ -0,0 +1,35 @@
+package gotinycsv
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrStop is returned by the function passed to ForEach to stop the iteration without an error.
+var ErrStop = errors.New("stop iteration")
+
+// ForEach decodes each record of a CSV into a reused struct and calls "fn" with it.
+// The iteration stops when "fn" returns an error, which is returned by ForEach unless it is ErrStop.
+// "row" is overwritten by the next record, so copy it if it must be kept.
+// The other arguments are the same as NewDecoder, but it is in safe mode like LoadAs.
+func ForEach[T any](r io.Reader, topmergin int, fn func(row *T, pos Position) error, ops ...string) error {
+	return ForEachWith(r, fn, WithSkipRows(topmergin), withOps(ops))
+}
+
+// ForEachWith is the same as ForEach except that the settings are given by "opts".
+func ForEachWith[T any](r io.Reader, fn func(row *T, pos Position) error, opts ...Option) error {
+	dec := newDecoder(r, newConfig(opts...).safe())
+	var row T
+	for dec.Next() {
+		if err := dec.Decode(&row); err != nil {
+			return err
+		}
+		if err := fn(&row, dec.Position()); err != nil {
+			if errors.Is(err, ErrStop) {
+				return nil
+			}
+			return err
+		}
+	}
+	return dec.Err()
+}
@@ -0,0 +1,71 @@
+package gotinycsv
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ForEach(t *testing.T) {
+	csv := `No,Name
+1,Alex
+2,"Bert
+Jr."
+3,Carl
+`
+	type csventry struct {
+		No   int
+		Name string
+	}
+
+	// normal case 1
+	{
+		names := []string{}
+		positions := []Position{}
+		rows := []*csventry{}
+		err := ForEach(strings.NewReader(csv), 1, func(row *csventry, pos Position) error {
+			names = append(names, row.Name)
+			positions = append(positions, pos)
+			rows = append(rows, row)
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Alex", "Bert\nJr.", "Carl"}, names)
+		assert.Equal(t, []Position{{Row: 0, Line: 2}, {Row: 1, Line: 3}, {Row: 2, Line: 5}}, positions)
+		// the struct is reused
+		assert.Same(t, rows[0], rows[2])
+	}
+	// normal case 2 (ErrStop)
+	{
+		count := 0
+		err := ForEach(strings.NewReader(csv), 1, func(row *csventry, pos Position) error {
+			count++
+			if row.No == 2 {
+				return ErrStop
+			}
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+	}
+	// illegal case 1 (error from fn)
+	{
+		err := ForEach(strings.NewReader(csv), 1, func(row *csventry, pos Position) error {
+			return fmt.Errorf("failed at line %d", pos.Line)
+		})
+
+		assert.EqualError(t, err, "failed at line 2")
+	}
+	// illegal case 2 (struct fields less than CSV fields)
+	{
+		err := ForEach(strings.NewReader(csv), 1, func(row *struct{ No int }, pos Position) error {
+			return nil
+		})
+
+		assert.EqualError(t, err, "number of fields in the defined structure may not match the number of fields in the CSV.")
+	}
+}
@@ -0,0 +1,71 @@
+package gotinycsv
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// validateElemType checks that "t" can be used as an element of the load destination under "cfg".
+// The plan of the struct is compiled once per type.
+func validateElemType(t reflect.Type, cfg *config) error {
+	if isMapElem(t) {
+		return nil
+	}
+	_, err := checkedPlan(t, cfg)
+	return err
+}
+
+// LoadAs loads a CSV and returns it as []T.
+// "T" is a struct, a pointer to struct, map[string]string or map[string]interface{}, and is validated once per type.
+// The other arguments are the same as Load.
+// Unlike Load, it is in safe mode which never writes unexported fields (see DefaultUnexported).
+func LoadAs[T any](r io.Reader, topmergin int, maxrows int, ops ...string) ([]T, error) {
+	return LoadAsWith[T](r, WithSkipRows(topmergin), WithMaxRows(maxrows), withOps(ops))
+}
+
+// LoadAsWith loads a CSV with options and returns it as []T.
+// It is the same as LoadAs except that the settings are given by "opts".
+func LoadAsWith[T any](r io.Reader, opts ...Option) ([]T, error) {
+	cfg := newConfig(opts...).safe()
+	if err := validateElemType(reflect.TypeOf((*T)(nil)).Elem(), cfg); err != nil {
+		return nil, err
+	}
+	var out []T
+	if err := load(r, &out, cfg); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LoadVerticallyAs loads a CSV with fileds arranged vertically and returns it as []T.
+// "T" is a struct or a pointer to struct, and is validated once per type.
+// The other arguments are the same as LoadVertically.
+// Unlike LoadVertically, it is in safe mode which never writes unexported fields (see DefaultUnexported).
+func LoadVerticallyAs[T any](r io.Reader, topmergin int, leftmergin int, maxcols int, ops ...string) ([]T, error) {
+	if r == nil {
+		return nil, fmt.Errorf("reader is nil")
+	}
+	if maxcols == 0 {
+		return nil, fmt.Errorf("maxcols is 0")
+	}
+	return LoadVerticallyAsWith[T](r, WithSkipRows(topmergin), WithSkipColumns(leftmergin), WithMaxColumns(maxcols), withOps(ops))
+}
+
+// LoadVerticallyAsWith loads a CSV with fileds arranged vertically with options and returns it as []T.
+// It is the same as LoadVerticallyAs except that the settings are given by "opts", and WithMaxColumns(0) reads all columns.
+func LoadVerticallyAsWith[T any](r io.Reader, opts ...Option) ([]T, error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if isMapElem(t) {
+		return nil, fmt.Errorf("elements of slice must be struct")
+	}
+	cfg := newConfig(opts...).safe()
+	if err := validateElemType(t, cfg); err != nil {
+		return nil, err
+	}
+	var out []T
+	if err := loadVertically(r, &out, cfg); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
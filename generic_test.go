@@ -0,0 +1,136 @@
+package gotinycsv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_validateElemType(t *testing.T) {
+	// normal case 1
+	{
+		type teststruct struct {
+			a int
+			b string
+			c time.Time
+		}
+
+		cfg := newConfig(withTraditional())
+		assert.NoError(t, validateElemType(reflect.TypeOf(teststruct{}), cfg))
+		assert.NoError(t, validateElemType(reflect.TypeOf(&teststruct{}), cfg))
+		assert.NoError(t, validateElemType(reflect.TypeOf(map[string]string{}), cfg))
+		_, ok := plans.Load(reflect.TypeOf(teststruct{}))
+		assert.True(t, ok)
+	}
+	// illegal case 1 (not struct)
+	{
+		assert.EqualError(t, validateElemType(reflect.TypeOf(0), newConfig()), "elements of slice must be struct")
+	}
+	// normal case 2 (unsupported unexported field is skipped in safe mode)
+	{
+		type teststruct struct {
+			A int
+			b []int
+		}
+
+		cfg := &config{unexported: UnexportedSkip}
+		assert.NoError(t, validateElemType(reflect.TypeOf(teststruct{}), cfg))
+		cfg.unexported = UnexportedError
+		assert.EqualError(t, validateElemType(reflect.TypeOf(teststruct{}), cfg), "unexported field b cannot be set in safe mode")
+	}
+	// illegal case 2 (unsupported field type)
+	{
+		type teststruct struct {
+			a []int
+		}
+
+		assert.EqualError(t, validateElemType(reflect.TypeOf(teststruct{}), newConfig(withTraditional())), "Unsupported types are used in structure fields")
+	}
+}
+
+func Test_LoadAs(t *testing.T) {
+	csv := `No,Name,Age
+1,Alex,41
+2,Bert,42
+`
+	// normal case 1
+	{
+		type csventry struct {
+			No   int
+			Name string
+			Age  int
+		}
+
+		entries, err := LoadAs[csventry](strings.NewReader(csv), 1, 10)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []csventry{{1, "Alex", 41}, {2, "Bert", 42}}, entries)
+	}
+	// normal case 2 (pointer)
+	{
+		type csventry struct {
+			No   int
+			Name string
+			Age  int
+		}
+
+		entries, err := LoadAs[*csventry](strings.NewReader(csv), 1, 10)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(entries))
+		assert.Equal(t, "Bert", entries[1].Name)
+	}
+	// normal case 3 (map)
+	{
+		entries, err := LoadAs[map[string]string](strings.NewReader(csv), 1, 10)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Alex", entries[0]["Name"])
+	}
+	// illegal case 1 (not struct)
+	{
+		entries, err := LoadAs[int](strings.NewReader(csv), 1, 10)
+
+		assert.EqualError(t, err, "elements of slice must be struct")
+		assert.Nil(t, entries)
+	}
+	// illegal case 2 (error from Load)
+	{
+		type csventry struct {
+			No int
+		}
+
+		entries, err := LoadAs[csventry](strings.NewReader(csv), 1, 10)
+
+		assert.EqualError(t, err, "number of fields in the defined structure may not match the number of fields in the CSV.")
+		assert.Nil(t, entries)
+	}
+}
+
+func Test_LoadVerticallyAs(t *testing.T) {
+	csv := `No,1,2
+Name,Alex,Bert
+`
+	// normal case
+	{
+		type csventry struct {
+			No   int
+			Name string
+		}
+
+		entries, err := LoadVerticallyAs[csventry](strings.NewReader(csv), 0, 1, 10)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []csventry{{1, "Alex"}, {2, "Bert"}}, entries)
+	}
+	// illegal case (map)
+	{
+		entries, err := LoadVerticallyAs[map[string]string](strings.NewReader(csv), 0, 1, 10)
+
+		assert.EqualError(t, err, "elements of slice must be struct")
+		assert.Nil(t, entries)
+	}
+}
@@ -1,8 +1,11 @@
 module github.com/ibbbpbbbp/gotinycsv
 
-go 1.17
+go 1.23
 
-require github.com/stretchr/testify v1.7.1
+require (
+	github.com/stretchr/testify v1.7.1
+	golang.org/x/text v0.21.0
+)
 
 require (
 	github.com/davecgh/go-spew v1.1.0 // indirect
@@ -0,0 +1,123 @@
+package gotinycsv
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_columnBindings(t *testing.T) {
+	// normal case 1 (positional)
+	{
+		columns, explicit, err := columnBindings([]fieldSpec{{}, {}, {}})
+		assert.NoError(t, err)
+		assert.False(t, explicit)
+		assert.Equal(t, []int{0, 1, 2}, columns)
+	}
+	// normal case 2 (index and skip)
+	{
+		columns, explicit, err := columnBindings([]fieldSpec{{index: 3, hasIndex: true}, {}, {skip: true}, {index: 1, hasIndex: true}})
+		assert.NoError(t, err)
+		assert.True(t, explicit)
+		assert.Equal(t, []int{-1, 3, -1, 0, 1}, columns)
+	}
+	// illegal case (column bound twice)
+	{
+		_, _, err := columnBindings([]fieldSpec{{index: 1, hasIndex: true}, {index: 1, hasIndex: true}})
+		assert.EqualError(t, err, "column 1 is bound to multiple fields")
+	}
+}
+
+func Test_Load_index(t *testing.T) {
+	csv := `No,Memo,Name,Country,Birth,Note
+1,x,Alex,JP,1999.01.01,a
+2,y,Bert,US,2001.02.02,b
+`
+	// normal case 1 (pick columns from a wide file)
+	{
+		type csventry struct {
+			No    int
+			Name  string    `csv:"#2"`
+			Birth time.Time `csv:",index=4"`
+			Memo  []int     `csv:"-"`
+		}
+
+		entries := []csventry{}
+		assert.NoError(t, Load(strings.NewReader(csv), 1, 10, &entries))
+		assert.Equal(t, 2, len(entries))
+		assert.Equal(t, 2, entries[1].No)
+		assert.Equal(t, "Bert", entries[1].Name)
+		assert.Equal(t, "2001-02-02 00:00:00 +0000 UTC", entries[1].Birth.String())
+		assert.Nil(t, entries[1].Memo)
+	}
+	// normal case 2 (fields following an indexed field)
+	{
+		type csventry struct {
+			Name    string `csv:"#2"`
+			Country string
+			No      int `csv:"#0"`
+		}
+
+		entries, err := LoadAs[csventry](strings.NewReader(csv), 1, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, []csventry{{"Alex", "JP", 1}, {"Bert", "US", 2}}, entries)
+	}
+	// normal case 3 (LoadVertically)
+	{
+		csv := `Name,Alex,Bert
+Memo,x,y
+Age,41,42
+`
+		type csventry struct {
+			Name string
+			Age  int `csv:"#2"`
+		}
+
+		entries := []csventry{}
+		assert.NoError(t, LoadVertically(strings.NewReader(csv), 0, 1, 10, &entries))
+		assert.Equal(t, []csventry{{"Alex", 41}, {"Bert", 42}}, entries)
+	}
+	// normal case 4 (LoadColumns)
+	{
+		var columns struct {
+			Name    []string `csv:"#2"`
+			Country []string
+		}
+
+		assert.NoError(t, LoadColumns(strings.NewReader(csv), 1, 10, &columns))
+		assert.Equal(t, []string{"Alex", "Bert"}, columns.Name)
+		assert.Equal(t, []string{"JP", "US"}, columns.Country)
+	}
+	// normal case 5 (names for other csv libraries are ignored)
+	{
+		type csventry struct {
+			ID   int    `csv:"client_id"`
+			Memo string `csv:"memo,trim=none"`
+		}
+
+		entries := []csventry{}
+		assert.NoError(t, Load(strings.NewReader("1, a \n2, b \n"), 0, 10, &entries))
+		assert.Equal(t, []csventry{{1, " a "}, {2, " b "}}, entries)
+	}
+	// illegal case 1 (invalid index)
+	{
+		type csventry struct {
+			No int `csv:"#x"`
+		}
+
+		entries := []csventry{}
+		assert.EqualError(t, Load(strings.NewReader(csv), 1, 10, &entries), "invalid column index: x")
+	}
+	// illegal case 2 (column bound twice)
+	{
+		type csventry struct {
+			No   int
+			Memo string `csv:"#0"`
+		}
+
+		entries := []csventry{}
+		assert.EqualError(t, Load(strings.NewReader(csv), 1, 10, &entries), "column 0 is bound to multiple fields")
+	}
+}
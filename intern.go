@@ -0,0 +1,64 @@
+package gotinycsv
+
+import (
+	"strings"
+	"sync"
+)
+
+// InternStrings makes string fields share the same string for the same value,
+// which saves memory for low-cardinality columns such as country or status.
+// The "intern" tag option enables it per field, and WithIntern overrides it per call.
+var InternStrings = false
+
+// InternLimit is the maximum number of distinct values interned per structure field.
+// Values beyond the limit are stored without interning, so high-cardinality columns do not blow memory.
+// Interned values are kept only during a load call or the life of a Decoder.
+// WithInternLimit overrides it per call.
+var InternLimit = 1024
+
+// internTables is the intern tables of the structure fields, created for each load call or Decoder.
+type internTables struct {
+	tables sync.Map // *fieldPlan -> *internTable
+}
+
+// table returns the intern table of "f", creating it at the first call.
+func (ts *internTables) table(f *fieldPlan) *internTable {
+	if t, ok := ts.tables.Load(f); ok {
+		return t.(*internTable)
+	}
+	t, _ := ts.tables.LoadOrStore(f, &internTable{})
+	return t.(*internTable)
+}
+
+// internTable is the bounded table of interned values of a structure field.
+type internTable struct {
+	mu     sync.RWMutex
+	values map[string]string
+}
+
+// intern returns the interned string equal to "v", and whether it is interned.
+// Up to "limit" distinct values are interned.
+func (t *internTable) intern(v string, limit int) (string, bool) {
+	t.mu.RLock()
+	s, ok := t.values[v]
+	t.mu.RUnlock()
+	if ok {
+		return s, true
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if s, ok := t.values[v]; ok {
+		return s, true
+	}
+	if len(t.values) >= limit {
+		return v, false
+	}
+	if t.values == nil {
+		t.values = make(map[string]string)
+	}
+	// "v" may share the string backing the whole csv record
+	s = strings.Clone(v)
+	t.values[s] = s
+	return s, true
+}
@@ -0,0 +1,91 @@
+package gotinycsv
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_internTable(t *testing.T) {
+	// normal case
+	{
+		table := &internTable{}
+		a, ok := table.intern(strings.Repeat("a", 3), InternLimit)
+		assert.True(t, ok)
+		b, ok := table.intern(strings.Repeat("a", 3), InternLimit)
+		assert.True(t, ok)
+		assert.Same(t, unsafe.StringData(a), unsafe.StringData(b))
+	}
+	// normal case (bounded)
+	{
+		table := &internTable{}
+		for i := 0; i < InternLimit; i++ {
+			_, ok := table.intern(fmt.Sprint(i), InternLimit)
+			assert.True(t, ok)
+		}
+		_, ok := table.intern("over", InternLimit)
+		assert.False(t, ok)
+		_, ok = table.intern("0", InternLimit)
+		assert.True(t, ok)
+		assert.Equal(t, InternLimit, len(table.values))
+	}
+}
+
+func Test_Load_intern(t *testing.T) {
+	csv := `1,JP,active
+2,US,active
+3,JP,closed
+`
+	// normal case 1 (per field)
+	{
+		type csventry struct {
+			no      int
+			country string `csv:",intern"`
+			status  string
+		}
+
+		entries := []csventry{}
+		err := Load(strings.NewReader(csv), 0, 10, &entries)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "JP", entries[2].country)
+		assert.Same(t, unsafe.StringData(entries[0].country), unsafe.StringData(entries[2].country))
+	}
+	// normal case 2 (global)
+	{
+		type csventry struct {
+			no      int
+			country string
+			status  string
+		}
+
+		InternStrings = true
+		entries := []csventry{}
+		err := Load(strings.NewReader(csv), 0, 10, &entries)
+		InternStrings = false
+
+		assert.NoError(t, err)
+		assert.Same(t, unsafe.StringData(entries[0].country), unsafe.StringData(entries[2].country))
+		assert.Same(t, unsafe.StringData(entries[0].status), unsafe.StringData(entries[1].status))
+	}
+	// normal case 3 (the tables are not carried over to the next load)
+	{
+		type csventry struct {
+			country string `csv:",intern"`
+		}
+
+		var distinct strings.Builder
+		for i := 0; i < InternLimit+100; i++ {
+			fmt.Fprintf(&distinct, "c%d\n", i)
+		}
+		entries := []csventry{}
+		assert.NoError(t, Load(strings.NewReader(distinct.String()), 0, 0, &entries))
+
+		entries = []csventry{}
+		assert.NoError(t, Load(strings.NewReader("JP\nJP\n"), 0, 10, &entries))
+		assert.Same(t, unsafe.StringData(entries[0].country), unsafe.StringData(entries[1].country))
+	}
+}
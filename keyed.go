@@ -0,0 +1,110 @@
+package gotinycsv
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// DuplicateKeyPolicy specifies how rows with the same key are handled when loading into a map.
+type DuplicateKeyPolicy int
+
+const (
+	// DuplicateKeyError emits an error for a duplicate key.
+	DuplicateKeyError DuplicateKeyPolicy = iota
+	// DuplicateKeyFirst keeps the first row of the duplicate key.
+	DuplicateKeyFirst
+	// DuplicateKeyLast keeps the last row of the duplicate key.
+	DuplicateKeyLast
+)
+
+// DefaultDuplicateKey is the duplicate key policy applied when the key field does not have a "dup" tag option.
+// It is fixed at the start of each call, and WithDuplicateKey overrides it per call.
+var DefaultDuplicateKey = DuplicateKeyError
+
+func parseDuplicateKeyPolicy(s string) (DuplicateKeyPolicy, error) {
+	switch s {
+	case "error":
+		return DuplicateKeyError, nil
+	case "first":
+		return DuplicateKeyFirst, nil
+	case "last":
+		return DuplicateKeyLast, nil
+	}
+	return DuplicateKeyError, fmt.Errorf("unknown duplicate key policy: %s", s)
+}
+
+func mapRefPointer(i interface{}) (*reflect.Value, bool) {
+	ref := reflect.ValueOf(i)
+	if ref.Kind() != reflect.Ptr || ref.Elem().Kind() != reflect.Map {
+		return nil, false
+	}
+	refp := ref.Elem()
+	return &refp, true
+}
+
+// loadMap loads rows into a temporary slice by "load", then sets them into the map "ref"
+// keyed by the structure field tagged with `csv:",key"`.
+func loadMap(ref reflect.Value, cfg *config, load func(out interface{}) error) error {
+	mt := ref.Type()
+	elemt := mt.Elem()
+	structt := elemt
+	if structt.Kind() == reflect.Ptr {
+		structt = structt.Elem()
+	}
+	specs, err := structFieldSpecs(structt)
+	if err != nil {
+		return err
+	}
+	key := -1
+	for i, spec := range specs {
+		if !spec.key {
+			continue
+		}
+		if key >= 0 {
+			return fmt.Errorf("key field must be only one")
+		}
+		key = i
+	}
+	if key < 0 {
+		return fmt.Errorf("key field is not found (did you forget `csv:\",key\"`?)")
+	}
+	if !structt.Field(key).Type.AssignableTo(mt.Key()) {
+		return fmt.Errorf("key field type %s is not assignable to %s", structt.Field(key).Type, mt.Key())
+	}
+	// the key field would be skipped in safe mode, and every key would be the zero value
+	safe := cfg.unexported != UnexportedSet
+	if sf := structt.Field(key); safe && !sf.IsExported() {
+		return fmt.Errorf("unexported field %s cannot be set in safe mode", sf.Name)
+	}
+
+	slice := reflect.New(reflect.SliceOf(elemt))
+	if err := load(slice.Interface()); err != nil {
+		return err
+	}
+
+	m := reflect.MakeMapWithSize(mt, slice.Elem().Len())
+	for i := 0; i < slice.Elem().Len(); i++ {
+		elem := slice.Elem().Index(i)
+		s := elem
+		if s.Kind() == reflect.Ptr {
+			s = s.Elem()
+		}
+		k := s.Field(key)
+		if !safe {
+			k = reflect.NewAt(k.Type(), unsafe.Pointer(k.UnsafeAddr())).Elem()
+		}
+		if m.MapIndex(k).IsValid() {
+			switch specs[key].duplicateKey(cfg) {
+			case DuplicateKeyFirst:
+				continue
+			case DuplicateKeyLast:
+			default:
+				return fmt.Errorf("duplicate key: %v", k.Interface())
+			}
+		}
+		m.SetMapIndex(k, elem)
+	}
+	ref.Set(m)
+	return nil
+}
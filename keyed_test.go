@@ -0,0 +1,117 @@
+package gotinycsv
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Load_keyed(t *testing.T) {
+	csv := `ID,Name,Age
+A1,Alex,41
+B2,Bert,42
+A1,Carl,32
+`
+	// normal case 1 (map[K]*T, last wins)
+	{
+		type csventry struct {
+			id   string `csv:",key,dup=last"`
+			name string
+			age  int
+		}
+
+		entries := map[string]*csventry{}
+		err := Load(strings.NewReader(csv), 1, 10, &entries)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(entries))
+		assert.Equal(t, "Carl", entries["A1"].name)
+		assert.Equal(t, "Bert", entries["B2"].name)
+	}
+	// normal case 2 (map[K]T, first wins, nil map)
+	{
+		type csventry struct {
+			id   string `csv:",key,dup=first"`
+			name string
+			age  int
+		}
+
+		var entries map[string]csventry
+		err := Load(strings.NewReader(csv), 1, 10, &entries)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(entries))
+		assert.Equal(t, "Alex", entries["A1"].name)
+		assert.Equal(t, 42, entries["B2"].age)
+	}
+	// normal case 3 (LoadVertically, non-string key)
+	{
+		csv := `No,1,2,3
+Name,Alex,Bert,Carl
+`
+		type csventry struct {
+			No   int `csv:",key"`
+			Name string
+		}
+
+		entries := map[int]csventry{}
+		err := LoadVertically(strings.NewReader(csv), 0, 1, 10, &entries)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, len(entries))
+		assert.Equal(t, "Bert", entries[2].Name)
+	}
+	// illegal case 1 (duplicate key)
+	{
+		type csventry struct {
+			id   string `csv:",key"`
+			name string
+			age  int
+		}
+
+		entries := map[string]csventry{}
+		err := Load(strings.NewReader(csv), 1, 10, &entries)
+
+		assert.EqualError(t, err, "duplicate key: A1")
+	}
+	// illegal case 2 (no key field)
+	{
+		type csventry struct {
+			id   string
+			name string
+			age  int
+		}
+
+		entries := map[string]csventry{}
+		err := Load(strings.NewReader(csv), 1, 10, &entries)
+
+		assert.EqualError(t, err, "key field is not found (did you forget `csv:\",key\"`?)")
+	}
+	// illegal case 3 (key type mismatch)
+	{
+		type csventry struct {
+			id   string `csv:",key"`
+			name string
+			age  int
+		}
+
+		entries := map[int]csventry{}
+		err := Load(strings.NewReader(csv), 1, 10, &entries)
+
+		assert.EqualError(t, err, "key field type string is not assignable to int")
+	}
+	// illegal case 4 (multiple key fields)
+	{
+		type csventry struct {
+			id   string `csv:",key"`
+			name string `csv:",key"`
+			age  int
+		}
+
+		entries := map[string]csventry{}
+		err := Load(strings.NewReader(csv), 1, 10, &entries)
+
+		assert.EqualError(t, err, "key field must be only one")
+	}
+}
@@ -0,0 +1,265 @@
+package gotinycsv
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unsafe"
+)
+
+// ErrLTSVField is reported in a *csv.ParseError for an LTSV field without a label.
+var ErrLTSVField = errors.New("LTSV field without label")
+
+// ltsvLabels returns the label of each field of the struct "t" (or pointer to struct), or "" for the fields not mapped.
+// The label is given by the `ltsv:"label"` tag, or the field name. Fields tagged with `ltsv:"-"` or `csv:"-"` are not mapped.
+func ltsvLabels(t reflect.Type, plan *decodePlan, policy UnexportedPolicy) ([]string, error) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	labels := make([]string, len(plan.fields))
+	seen := map[string]bool{}
+	for i := range plan.fields {
+		f := &plan.fields[i]
+		if f.spec.skip || f.blank || f.skipped(policy) {
+			continue
+		}
+		label, ok := t.Field(i).Tag.Lookup("ltsv")
+		if label == "-" {
+			continue
+		}
+		if !ok {
+			label = f.name
+		}
+		if !validLTSVLabel(label) {
+			return nil, fmt.Errorf("invalid LTSV label: %s", label)
+		}
+		if seen[label] {
+			return nil, fmt.Errorf("LTSV label %s is bound to multiple fields", label)
+		}
+		seen[label] = true
+		labels[i] = label
+	}
+	return labels, nil
+}
+
+// validLTSVLabel reports whether "label" consists of [0-9A-Za-z_.-] as the LTSV specification.
+func validLTSVLabel(label string) bool {
+	if label == "" {
+		return false
+	}
+	for _, c := range []byte(label) {
+		if !('0' <= c && c <= '9' || 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || c == '_' || c == '.' || c == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// Load an LTSV (Labeled Tab-separated Values) text.
+// Each line is fields of "label:value" separated by tabs, such as "host:127.0.0.1\tstatus:200".
+// A value is set into the structure field of the same label, given by the `ltsv:"label"` tag or the field name.
+// Labels without a structure field are ignored, and the fields whose label is missing in a line are left zero.
+// A field without ":" is reported as ErrLTSVField in a *csv.ParseError.
+// Empty lines and comment lines of the dialect are skipped.
+// The field conversion is the same as Load in safe mode, and the other arguments are the same as Load.
+func LoadLTSV(r io.Reader, topmergin int, maxrows int, out interface{}, ops ...string) error {
+	return LoadLTSVWith(r, out, WithSkipRows(topmergin), WithMaxRows(maxrows), withOps(ops))
+}
+
+// LoadLTSVWith loads an LTSV text with options.
+// It is the same as LoadLTSV except that the settings are given by "opts".
+func LoadLTSVWith(r io.Reader, out interface{}, opts ...Option) error {
+	return loadLTSV(r, out, newConfig(opts...))
+}
+
+func loadLTSV(r io.Reader, out interface{}, cfg *config) error {
+	if r == nil {
+		return fmt.Errorf("reader is nil")
+	}
+	// the delimiter is always a tab, so it is not detected
+	c := *cfg
+	c.auto = false
+	r, cfg, err := c.input(r, false)
+	if err != nil {
+		return err
+	}
+	refp, err := sliceRefPointer(out)
+	if err != nil {
+		return err
+	}
+	elemt := refp.Type().Elem()
+	if isMapElem(elemt) {
+		return fmt.Errorf("elements of slice must be struct")
+	}
+	plan, err := checkedPlan(elemt, cfg)
+	if err != nil {
+		return err
+	}
+	labels, err := ltsvLabels(elemt, plan, cfg.unexported)
+	if err != nil {
+		return err
+	}
+	fields := make(map[string]int, len(labels))
+	for i, label := range labels {
+		if label != "" {
+			fields[label] = i
+		}
+	}
+
+	lines, err := readLines(r, cfg)
+	if err != nil {
+		return err
+	}
+
+	// create "out" for all rows
+	if err = ensureSliceCapacity(*refp, len(lines)); err != nil {
+		return err
+	}
+
+	for rows, l := range lines {
+		ref := elemRef(*refp, rows)
+		column := 1
+		for rest, more := l.text, true; more; {
+			var item string
+			item, rest, more = strings.Cut(rest, "\t")
+			label, value, ok := strings.Cut(item, ":")
+			if !ok {
+				return &csv.ParseError{StartLine: l.line, Line: l.line, Column: column, Err: ErrLTSVField}
+			}
+			column += len(item) + 1
+			i, ok := fields[label]
+			if !ok {
+				continue
+			}
+			if err = plan.setField(ref, i, value, cfg); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Write "in" as an LTSV text to "w".
+// "in" is a slice of struct or pointer to struct, which is written a line per element.
+// Each structure field is written as "label:value" with the label of LoadLTSV, in the order of the fields.
+// time.Time fields are formatted by the time-layout of "ops", and the types registered by RegisterEnum by their labels.
+// A value containing a tab or a newline is an error, since it cannot be represented in LTSV.
+// Unexported fields are handled in safe mode (see DefaultUnexported).
+func WriteLTSV(w io.Writer, in interface{}, ops ...string) error {
+	return WriteLTSVWith(w, in, withOps(ops))
+}
+
+// WriteLTSVWith writes "in" as an LTSV text to "w" with options.
+// It is the same as WriteLTSV except that the settings are given by "opts".
+func WriteLTSVWith(w io.Writer, in interface{}, opts ...Option) error {
+	return writeLTSV(w, in, newConfig(opts...))
+}
+
+func writeLTSV(w io.Writer, in interface{}, cfg *config) error {
+	if w == nil {
+		return fmt.Errorf("writer is nil")
+	}
+	ref := reflect.ValueOf(in)
+	if ref.Kind() == reflect.Ptr {
+		ref = ref.Elem()
+	}
+	if ref.Kind() != reflect.Slice {
+		return fmt.Errorf("in must be a slice")
+	}
+	elemt := ref.Type().Elem()
+	if isMapElem(elemt) {
+		return fmt.Errorf("elements of slice must be struct")
+	}
+	plan, err := checkedPlan(elemt, cfg)
+	if err != nil {
+		return err
+	}
+	labels, err := ltsvLabels(elemt, plan, cfg.unexported)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	var buf []byte
+	for rows := 0; rows < ref.Len(); rows++ {
+		elem := ref.Index(rows)
+		if elem.Kind() == reflect.Ptr {
+			if elem.IsNil() {
+				return fmt.Errorf("element %d is nil", rows)
+			}
+			elem = elem.Elem()
+		}
+		buf = buf[:0]
+		for i, label := range labels {
+			if label == "" {
+				continue
+			}
+			if len(buf) > 0 {
+				buf = append(buf, '\t')
+			}
+			buf = append(buf, label...)
+			buf = append(buf, ':')
+			n := len(buf)
+			if buf, err = appendEntity(buf, readableField(elem, i), cfg.timelayout); err != nil {
+				return err
+			}
+			if strings.ContainsAny(string(buf[n:]), "\t\r\n") {
+				return fmt.Errorf("LTSV value of %s must not contain tab or newline", label)
+			}
+		}
+		buf = append(buf, '\n')
+		if _, err = bw.Write(buf); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// readableField returns the "i"-th field of the struct "ref", which can be read by Interface even if it is unexported.
+func readableField(ref reflect.Value, i int) reflect.Value {
+	field := ref.Field(i)
+	if field.CanInterface() {
+		return field
+	}
+	if ref.CanAddr() {
+		return reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem()
+	}
+	// a copy of the struct is addressable
+	c := reflect.New(ref.Type()).Elem()
+	c.Set(ref)
+	return readableField(c, i)
+}
+
+// appendEntity appends the text of "ref", which is parsed back by setEntityViaRef.
+func appendEntity(buf []byte, ref reflect.Value, timelayout string) ([]byte, error) {
+	if _, ok := lookupEnum(ref.Type()); ok {
+		label, ok := EnumLabel(ref.Interface())
+		if !ok && !ref.IsZero() {
+			return buf, fmt.Errorf("no label for %s: %v", ref.Type(), ref.Interface())
+		}
+		// the zero value without a label is written as empty, which is parsed as the zero value
+		return append(buf, label...), nil
+	}
+	switch ref.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.AppendInt(buf, ref.Int(), 10), nil
+	case reflect.Float32:
+		return strconv.AppendFloat(buf, ref.Float(), 'g', -1, 32), nil
+	case reflect.Float64:
+		return strconv.AppendFloat(buf, ref.Float(), 'g', -1, 64), nil
+	case reflect.String:
+		return append(buf, ref.String()...), nil
+	case reflect.Struct:
+		if ref.Type() == timeType {
+			return ref.Interface().(time.Time).AppendFormat(buf, timelayout), nil
+		}
+	}
+	return buf, fmt.Errorf("Unsupported types are used in structure fields")
+}
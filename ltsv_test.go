@@ -0,0 +1,120 @@
+package gotinycsv
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testMethod int
+
+func Test_LoadLTSV(t *testing.T) {
+	type access struct {
+		Host   string `ltsv:"host"`
+		Time   time.Time
+		Status int     `ltsv:"status"`
+		Size   float64 `ltsv:"size"`
+		Memo   string  `ltsv:"-"`
+	}
+
+	// normal case 1 (missing and extra labels)
+	{
+		text := "host:127.0.0.1\tTime:2024-01-02\tstatus:200\tua:curl\n" +
+			"\n" +
+			"status: 404 \thost:10.0.0.1\r\n"
+		entries := []access{}
+		assert.NoError(t, LoadLTSV(strings.NewReader(text), 0, 10, &entries, "2006-01-02"))
+		assert.Equal(t, []access{
+			{Host: "127.0.0.1", Time: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), Status: 200},
+			{Host: "10.0.0.1", Status: 404},
+		}, entries)
+	}
+	// normal case 2 (empty values, values with ":" and options)
+	{
+		type entry struct {
+			Host string `ltsv:"host"`
+			Path string `ltsv:"path"`
+		}
+		entries := []entry{}
+		assert.NoError(t, LoadLTSVWith(strings.NewReader("# comment\nhost:\tpath:/a:b\n"), &entries, WithComment('#')))
+		assert.Equal(t, []entry{{"", "/a:b"}}, entries)
+	}
+	// illegal case 1 (a field without a label)
+	{
+		entries := []access{}
+		err := LoadLTSV(strings.NewReader("host:a\nhost:b\tstatus\n"), 0, 10, &entries)
+		var pe *csv.ParseError
+		assert.True(t, errors.As(err, &pe))
+		assert.ErrorIs(t, err, ErrLTSVField)
+		assert.Equal(t, 2, pe.Line)
+		assert.Equal(t, 8, pe.Column)
+	}
+	// illegal case 2 (labels and rows)
+	{
+		type dup struct {
+			A string `ltsv:"a"`
+			B string `ltsv:"a"`
+		}
+		assert.EqualError(t, LoadLTSV(strings.NewReader("a:1\n"), 0, 10, &[]dup{}), "LTSV label a is bound to multiple fields")
+		type bad struct {
+			A string `ltsv:"a b"`
+		}
+		assert.EqualError(t, LoadLTSV(strings.NewReader("a:1\n"), 0, 10, &[]bad{}), "invalid LTSV label: a b")
+		assert.EqualError(t, LoadLTSV(strings.NewReader("host:a\nhost:b\n"), 0, 1, &[]access{}), "rows are too large")
+		assert.EqualError(t, LoadLTSV(strings.NewReader("\n"), 0, 10, &[]access{}), "topmergin is too large")
+	}
+}
+
+func Test_WriteLTSV(t *testing.T) {
+	assert.NoError(t, RegisterEnum(map[string]testMethod{"GET": 1, "POST": 2}, false))
+	type access struct {
+		Host   string     `ltsv:"host"`
+		Method testMethod `ltsv:"method"`
+		Time   time.Time  `ltsv:"time"`
+		Status int        `ltsv:"status"`
+		Size   float32    `ltsv:"size"`
+		Memo   string     `csv:"-"`
+		_      int
+	}
+
+	// normal case (round trip)
+	{
+		in := []*access{
+			{Host: "127.0.0.1", Method: 1, Time: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), Status: 200, Size: 1.5, Memo: "x"},
+			{Host: "10.0.0.1", Status: 404},
+		}
+		var buf bytes.Buffer
+		assert.NoError(t, WriteLTSV(&buf, in, "2006-01-02"))
+		assert.Equal(t, "host:127.0.0.1\tmethod:GET\ttime:2024-01-02\tstatus:200\tsize:1.5\n"+
+			"host:10.0.0.1\tmethod:\ttime:0001-01-01\tstatus:404\tsize:0\n", buf.String())
+
+		out := []access{}
+		assert.NoError(t, LoadLTSV(&buf, 0, 10, &out, "2006-01-02"))
+		assert.Equal(t, []access{{Host: "127.0.0.1", Method: 1, Time: in[0].Time, Status: 200, Size: 1.5}, {Host: "10.0.0.1", Status: 404}}, out)
+
+		type private struct {
+			host string `ltsv:"host"`
+		}
+		buf.Reset()
+		assert.EqualError(t, WriteLTSV(&buf, []private{{"a"}}), "unexported field host cannot be set in safe mode")
+		assert.NoError(t, WriteLTSVWith(&buf, []private{{"a"}}, WithUnexported(UnexportedSet)))
+		assert.Equal(t, "host:a\n", buf.String())
+		buf.Reset()
+		assert.NoError(t, WriteLTSVWith(&buf, &[]private{{"a"}}, WithUnexported(UnexportedSkip)))
+		assert.Equal(t, "\n", buf.String())
+	}
+	// illegal case
+	{
+		var buf bytes.Buffer
+		assert.EqualError(t, WriteLTSV(&buf, []access{{Host: "a\tb"}}), "LTSV value of host must not contain tab or newline")
+		assert.EqualError(t, WriteLTSV(&buf, []access{{Method: 3}}), "no label for gotinycsv.testMethod: 3")
+		assert.EqualError(t, WriteLTSV(&buf, []*access{nil}), "element 0 is nil")
+		assert.EqualError(t, WriteLTSV(&buf, access{}), "in must be a slice")
+		assert.Error(t, WriteLTSV(nil, []access{}))
+	}
+}
@@ -0,0 +1,160 @@
+package gotinycsv
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	stringMapType = reflect.TypeOf(map[string]string{})
+	anyMapType    = reflect.TypeOf(map[string]interface{}{})
+	anyType       = anyMapType.Elem()
+)
+
+func isMapElem(t reflect.Type) bool {
+	return t == stringMapType || t == anyMapType
+}
+
+// loadMaps reads all records from "cr" and sets them into the maps of "ref".
+// Unlike structs, all records are kept until the end to infer the types of the columns.
+func loadMaps(cr *csv.Reader, topmergin int, maxrows int, ref reflect.Value, cfg *config) error {
+	records := make([][]string, 0, maxrows)
+
+	// the last line of the top margin is the header
+	var header []string
+
+	rows := 0
+	for ; ; rows++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if rows < topmergin {
+			header = record
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if maxrows > 0 && rows >= topmergin+maxrows {
+			return fmt.Errorf("rows are too large")
+		}
+		if header != nil {
+			if record, err = fitRecord(cfg.columns, cr, record, len(header), false); err != nil {
+				return err
+			}
+		}
+		records = append(records, record)
+	}
+	if rows <= topmergin {
+		return fmt.Errorf("topmergin is too large")
+	}
+
+	// create "out" for all rows
+	if err := ensureSliceCapacity(ref, rows-topmergin); err != nil {
+		return err
+	}
+
+	return setMapsViaRef(ref, header, records, cfg)
+}
+
+// setMapsViaRef sets csv records into the maps of "ref" keyed by "header".
+// The values of map[string]interface{} are converted to the type inferred for each column.
+func setMapsViaRef(ref reflect.Value, header []string, records [][]string, cfg *config) error {
+	if header == nil {
+		return fmt.Errorf("header is required for elements of map (topmergin must be 1 or more)")
+	}
+	keys := make([]string, len(header))
+	for i, h := range header {
+		keys[i] = cfg.trim.apply(h)
+	}
+
+	convs := make([]func(string) interface{}, len(keys))
+	if ref.Type().Elem() == anyMapType {
+		for cols := range convs {
+			convs[cols] = inferColumn(records, cols, cfg)
+		}
+	}
+
+	for rows, record := range records {
+		m := reflect.MakeMapWithSize(ref.Type().Elem(), len(keys))
+		for cols, v := range record {
+			if cols >= len(keys) {
+				return fmt.Errorf("number of fields in the header may not match the number of fields in the CSV.")
+			}
+			s := cfg.trim.apply(v)
+			if convs[cols] != nil {
+				value := reflect.ValueOf(convs[cols](s))
+				if !value.IsValid() {
+					// the zero Value deletes the key, so nil is set as an interface value
+					value = reflect.Zero(anyType)
+				}
+				m.SetMapIndex(reflect.ValueOf(keys[cols]), value)
+			} else {
+				m.SetMapIndex(reflect.ValueOf(keys[cols]), reflect.ValueOf(s))
+			}
+		}
+		ref.Index(rows).Set(m)
+	}
+	return nil
+}
+
+// inferColumn returns the converter for the "cols" column of "records".
+// The type is detected in order of int64, float64, bool, time.Time and string,
+// the first one that accepts all non-empty fields is chosen.
+// Empty fields are converted to nil except for string columns.
+func inferColumn(records [][]string, cols int, cfg *config) func(string) interface{} {
+	timelayout := cfg.timelayout
+	parsers := []func(string) (interface{}, error){
+		func(s string) (interface{}, error) { return strconv.ParseInt(s, 10, 64) },
+		func(s string) (interface{}, error) { return strconv.ParseFloat(s, 64) },
+		func(s string) (interface{}, error) {
+			switch strings.ToLower(s) {
+			case "true":
+				return true, nil
+			case "false":
+				return false, nil
+			}
+			return nil, fmt.Errorf("not bool: %s", s)
+		},
+		func(s string) (interface{}, error) { return time.Parse(timelayout, s) },
+	}
+
+	empty := true
+	for _, parse := range parsers {
+		accepted := true
+		for _, record := range records {
+			if cols >= len(record) {
+				continue
+			}
+			s := cfg.trim.apply(record[cols])
+			if s == "" {
+				continue
+			}
+			empty = false
+			if _, err := parse(s); err != nil {
+				accepted = false
+				break
+			}
+		}
+		if empty {
+			break
+		}
+		if accepted {
+			parse := parse
+			return func(s string) interface{} {
+				if s == "" {
+					return nil
+				}
+				v, _ := parse(s)
+				return v
+			}
+		}
+	}
+	return func(s string) interface{} { return s }
+}
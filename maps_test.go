@@ -0,0 +1,67 @@
+package gotinycsv
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Load_maps(t *testing.T) {
+	csv := `No,Name,Married,Height,Birth,Note
+1,Alex,true,74.5,1999.01.01,
+2,Bert,FALSE,68,2001.02.02,x
+3,Carl,,70,,
+`
+	// normal case 1 (map[string]string)
+	{
+		entries := []map[string]string{}
+		err := Load(strings.NewReader(csv), 1, 10, &entries)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, len(entries))
+		assert.Equal(t, map[string]string{"No": "1", "Name": "Alex", "Married": "true", "Height": "74.5", "Birth": "1999.01.01", "Note": ""}, entries[0])
+		assert.Equal(t, "FALSE", entries[1]["Married"])
+		assert.Equal(t, "x", entries[1]["Note"])
+	}
+	// normal case 2 (map[string]interface{} with type inference)
+	{
+		entries := []map[string]interface{}{}
+		err := Load(strings.NewReader(csv), 1, 10, &entries)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, len(entries))
+		assert.Equal(t, int64(1), entries[0]["No"])
+		assert.Equal(t, "Alex", entries[0]["Name"])
+		assert.Equal(t, true, entries[0]["Married"])
+		assert.Equal(t, false, entries[1]["Married"])
+		assert.Nil(t, entries[2]["Married"])
+		assert.Equal(t, 74.5, entries[0]["Height"])
+		assert.Equal(t, float64(68), entries[1]["Height"])
+		assert.Equal(t, time.Date(1999, 1, 1, 0, 0, 0, 0, time.UTC), entries[0]["Birth"])
+		assert.Nil(t, entries[2]["Birth"])
+		birth, ok := entries[2]["Birth"]
+		assert.True(t, ok)
+		assert.Nil(t, birth)
+		assert.Equal(t, "", entries[0]["Note"])
+		assert.Equal(t, "x", entries[1]["Note"])
+	}
+	// normal case 3 (an empty cell keeps the key)
+	{
+		entries := []map[string]interface{}{}
+		err := Load(strings.NewReader("a,b\n1,1\n2,\n"), 1, 10, &entries)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []map[string]interface{}{{"a": int64(1), "b": int64(1)}, {"a": int64(2), "b": nil}}, entries)
+		_, ok := entries[1]["b"]
+		assert.True(t, ok)
+	}
+	// illegal case (without header)
+	{
+		entries := []map[string]string{}
+		err := Load(strings.NewReader(csv), 0, 10, &entries)
+
+		assert.EqualError(t, err, "header is required for elements of map (topmergin must be 1 or more)")
+	}
+}
@@ -0,0 +1,215 @@
+package gotinycsv
+
+// Option sets a setting of a load.
+type Option func(*config)
+
+// Config is an immutable set of settings built from options.
+// It can be shared by goroutines, and passed to a load by WithConfig.
+//
+//	cfg := gotinycsv.NewConfig(gotinycsv.WithSkipRows(1), gotinycsv.WithTimeLayout("2006-01-02"))
+//	err := gotinycsv.LoadWith(r, &out, gotinycsv.WithConfig(cfg), gotinycsv.WithMaxRows(100))
+type Config struct {
+	cfg config
+}
+
+// NewConfig returns the Config of "opts" applied to the defaults.
+// The package defaults, such as DefaultColumns, DefaultUnexported, DefaultTrim and InternLimit, are fixed at the time NewConfig is called.
+func NewConfig(opts ...Option) Config {
+	return Config{cfg: *newConfig(opts...)}
+}
+
+// With returns a copy of the Config with "opts" applied.
+func (c Config) With(opts ...Option) Config {
+	cfg := c.cfg
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return Config{cfg: cfg}
+}
+
+// WithConfig replaces all settings with "c". The options after it are applied to the copy.
+func WithConfig(c Config) Option {
+	return func(cfg *config) {
+		*cfg = c.cfg
+	}
+}
+
+// WithSkipRows skips "n" lines from the top line (topmergin). The last of them is the header for maps.
+func WithSkipRows(n int) Option {
+	return func(cfg *config) {
+		cfg.topmergin, cfg.hasTopmergin = n, true
+	}
+}
+
+// WithMaxRows emits an error when the number of rows read exceeds "n" (maxrows).
+// If "n" is 0, the entire data is read.
+func WithMaxRows(n int) Option {
+	return func(cfg *config) {
+		cfg.maxrows = n
+	}
+}
+
+// WithSkipColumns skips "n" columns from the left edge (leftmergin) of a CSV with fields arranged vertically.
+func WithSkipColumns(n int) Option {
+	return func(cfg *config) {
+		cfg.leftmergin = n
+	}
+}
+
+// WithMaxColumns emits an error when the number of columns of a CSV with fields arranged vertically exceeds "n" (maxcols).
+// If "n" is 0, the entire data is read.
+func WithMaxColumns(n int) Option {
+	return func(cfg *config) {
+		cfg.maxcols = n
+	}
+}
+
+// WithWorkers sets the number of goroutines of the parallel loads.
+// If "n" is 0 or less, runtime.GOMAXPROCS(0) is used.
+func WithWorkers(n int) Option {
+	return func(cfg *config) {
+		cfg.workers = n
+	}
+}
+
+// WithTimeLayout sets the layout of time.Time fields.
+func WithTimeLayout(layout string) Option {
+	return func(cfg *config) {
+		cfg.timelayout = layout
+	}
+}
+
+// WithDialect sets the format of a CSV. The time-layout is also set if it is not empty.
+func WithDialect(d Dialect) Option {
+	return func(cfg *config) {
+		cfg.dialect = d
+		if d.TimeLayout != "" {
+			cfg.timelayout = d.TimeLayout
+		}
+	}
+}
+
+// WithAuto detects the delimiter, quote style, header, line ending and encoding from the first "size" bytes by Sniff.
+// If "size" is 0 or less, DefaultSniffSize is used. A detected header skips the first line unless WithSkipRows is given,
+// except for the CSV with fields arranged vertically.
+func WithAuto(size int) Option {
+	return func(cfg *config) {
+		cfg.auto, cfg.sniffSize = true, size
+	}
+}
+
+// WithEncoding sets the character encoding, which is detected by WithAuto if it is not given.
+func WithEncoding(e Encoding) Option {
+	return func(cfg *config) {
+		cfg.dialect.Encoding, cfg.hasEncoding = e, true
+	}
+}
+
+// WithCompression sets the compression format of the data, which is DefaultCompression if it is not given.
+func WithCompression(c Compression) Option {
+	return func(cfg *config) {
+		cfg.compression = c
+	}
+}
+
+// WithWidths sets the column widths of a fixed-width text, used for the fields without the "fw" tag.
+func WithWidths(widths ...int) Option {
+	widths = append([]int(nil), widths...)
+	return func(cfg *config) {
+		cfg.widths = widths
+	}
+}
+
+// WithWidthUnit sets how the positions and widths of a fixed-width text are counted.
+func WithWidthUnit(u WidthUnit) Option {
+	return func(cfg *config) {
+		cfg.widthUnit = u
+	}
+}
+
+// WithTrim sets the trim policy of the fields without a "trim" tag option, which is DefaultTrim if it is not given.
+func WithTrim(p TrimPolicy) Option {
+	return func(cfg *config) {
+		cfg.trim = p
+	}
+}
+
+// WithDuplicateKey sets the duplicate key policy of the key field without a "dup" tag option,
+// which is DefaultDuplicateKey if it is not given.
+func WithDuplicateKey(p DuplicateKeyPolicy) Option {
+	return func(cfg *config) {
+		cfg.dup = p
+	}
+}
+
+// WithCloneStrings sets whether all string fields are cloned, which is CloneStrings if it is not given.
+func WithCloneStrings(clone bool) Option {
+	return func(cfg *config) {
+		cfg.clone = clone
+	}
+}
+
+// WithIntern sets whether all string fields are interned, which is InternStrings if it is not given.
+func WithIntern(intern bool) Option {
+	return func(cfg *config) {
+		cfg.intern = intern
+	}
+}
+
+// WithInternLimit sets the maximum number of distinct values interned per field, which is InternLimit if it is not given.
+func WithInternLimit(n int) Option {
+	return func(cfg *config) {
+		cfg.internLimit = n
+	}
+}
+
+// WithComma sets the field delimiter.
+func WithComma(r rune) Option {
+	return func(cfg *config) {
+		cfg.dialect.Comma = r
+	}
+}
+
+// WithComment sets the character starting a comment line.
+func WithComment(r rune) Option {
+	return func(cfg *config) {
+		cfg.dialect.Comment = r
+	}
+}
+
+// WithLazyQuotes allows a quote in an unquoted field and a non-doubled quote in a quoted field.
+func WithLazyQuotes(lazy bool) Option {
+	return func(cfg *config) {
+		cfg.dialect.LazyQuotes = lazy
+	}
+}
+
+// WithTrimLeadingSpace ignores leading white spaces of a field.
+func WithTrimLeadingSpace(trim bool) Option {
+	return func(cfg *config) {
+		cfg.dialect.TrimLeadingSpace = trim
+	}
+}
+
+// WithFieldsPerRecord is the same as csv.Reader.FieldsPerRecord.
+func WithFieldsPerRecord(n int) Option {
+	return func(cfg *config) {
+		cfg.dialect.FieldsPerRecord = n
+	}
+}
+
+// WithColumnPolicy sets how records with a different number of columns are handled, instead of DefaultColumns.
+func WithColumnPolicy(policy ColumnPolicy) Option {
+	return func(cfg *config) {
+		cfg.columns = policy
+	}
+}
+
+// WithUnexported sets how unexported fields are handled, instead of the default of each function.
+// UnexportedSet enables writing unexported fields for the functions taking "out interface{}".
+// The generic functions are always in safe mode, so UnexportedSet is treated as UnexportedError.
+func WithUnexported(policy UnexportedPolicy) Option {
+	return func(cfg *config) {
+		cfg.unexported, cfg.hasUnexported = policy, true
+	}
+}
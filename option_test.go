@@ -0,0 +1,146 @@
+package gotinycsv
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_LoadWith(t *testing.T) {
+	csv := `No,Name,Birth
+1,Alex,1999-01-01
+2,Bert,2001-02-02
+3,Carl,2002-05-05
+`
+	type csventry struct {
+		No    int
+		Name  string
+		Birth time.Time
+	}
+
+	// normal case 1 (options)
+	{
+		entries := []csventry{}
+		err := LoadWith(strings.NewReader(csv), &entries, WithSkipRows(1), WithMaxRows(3), WithTimeLayout("2006-01-02"))
+		assert.NoError(t, err)
+		assert.Equal(t, 3, len(entries))
+		assert.Equal(t, "2001-02-02 00:00:00 +0000 UTC", entries[1].Birth.String())
+	}
+	// normal case 2 (Config shared by goroutines)
+	{
+		cfg := NewConfig(WithSkipRows(1), WithTimeLayout("2006-01-02"))
+		var wg sync.WaitGroup
+		results := make([][]csventry, 8)
+		for i := range results {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				assert.NoError(t, LoadWith(strings.NewReader(csv), &results[i], WithConfig(cfg)))
+			}(i)
+		}
+		wg.Wait()
+		for _, entries := range results {
+			assert.Equal(t, 3, len(entries))
+			assert.Equal(t, "Carl", entries[2].Name)
+		}
+
+		// With does not change the original Config
+		tsv := cfg.With(WithComma('\t'))
+		entries, err := LoadAsWith[csventry](strings.NewReader(strings.ReplaceAll(csv, ",", "\t")), WithConfig(tsv))
+		assert.NoError(t, err)
+		assert.Equal(t, 3, len(entries))
+		assert.Equal(t, rune(0), cfg.cfg.dialect.Comma)
+	}
+	// normal case 3 (the other functions)
+	{
+		cfg := NewConfig(WithSkipRows(1), WithTimeLayout("2006-01-02"), WithWorkers(2))
+
+		parallel := []csventry{}
+		assert.NoError(t, LoadParallelWith(strings.NewReader(csv), &parallel, WithConfig(cfg)))
+		assert.Equal(t, 3, len(parallel))
+		chunked := []csventry{}
+		assert.NoError(t, LoadReaderAtWith(strings.NewReader(csv), int64(len(csv)), &chunked, WithConfig(cfg)))
+		assert.Equal(t, parallel, chunked)
+
+		dec := NewDecoderWith(strings.NewReader(csv), WithConfig(cfg))
+		assert.True(t, dec.Next())
+		assert.Equal(t, []string{"No", "Name", "Birth"}, dec.Header())
+
+		rows := 0
+		assert.NoError(t, ForEachWith(strings.NewReader(csv), func(row *csventry, pos Position) error {
+			rows++
+			return nil
+		}, WithConfig(cfg)))
+		assert.Equal(t, 3, rows)
+		for row, err := range RowsWith[csventry](strings.NewReader(csv), WithConfig(cfg)) {
+			assert.NoError(t, err)
+			assert.Equal(t, "Alex", row.Name)
+			break
+		}
+	}
+	// normal case 4 (LoadVerticallyWith reads all columns by default)
+	{
+		csv := `Name,Alex,Bert
+No,1,2
+`
+		type csventry struct {
+			Name string
+			No   int
+		}
+
+		entries := []csventry{}
+		assert.NoError(t, LoadVerticallyWith(strings.NewReader(csv), &entries, WithSkipColumns(1)))
+		assert.Equal(t, []csventry{{"Alex", 1}, {"Bert", 2}}, entries)
+		entries, err := LoadVerticallyAsWith[csventry](strings.NewReader(csv), WithSkipColumns(1), WithMaxColumns(1))
+		assert.EqualError(t, err, "columns are too large")
+		assert.Nil(t, entries)
+	}
+	// normal case 5 (per-call field settings are fixed in Config, not read from the package defaults)
+	{
+		type keyed struct {
+			ID   int    `csv:",key"`
+			Name string `csv:",trim=none"`
+			City string
+		}
+
+		cfg := NewConfig(WithTrim(TrimNone), WithDuplicateKey(DuplicateKeyLast), WithIntern(true), WithInternLimit(1))
+		DefaultTrim, DefaultDuplicateKey = TrimLeft, DuplicateKeyFirst
+		m := map[int]keyed{}
+		err := LoadWith(strings.NewReader("1, a , x \n1, b , y \n"), &m, WithConfig(cfg))
+		DefaultTrim, DefaultDuplicateKey = TrimBoth, DuplicateKeyError
+		assert.NoError(t, err)
+		assert.Equal(t, map[int]keyed{1: {1, " b ", " y "}}, m)
+
+		type country struct {
+			Code string
+		}
+		entries, err := LoadAsWith[country](strings.NewReader("JP\nUS\nJP\nUS\n"), WithConfig(cfg))
+		assert.NoError(t, err)
+		assert.Same(t, unsafe.StringData(entries[0].Code), unsafe.StringData(entries[2].Code))
+		assert.NotSame(t, unsafe.StringData(entries[1].Code), unsafe.StringData(entries[3].Code))
+
+		maps := []map[string]string{}
+		assert.NoError(t, LoadWith(strings.NewReader(" k \n v \n"), &maps, WithSkipRows(1), WithTrim(TrimNone)))
+		assert.Equal(t, []map[string]string{{" k ": " v "}}, maps)
+	}
+	// illegal case 1 (maxrows)
+	{
+		entries := []csventry{}
+		err := LoadWith(strings.NewReader(csv), &entries, WithSkipRows(1), WithMaxRows(2))
+		assert.EqualError(t, err, "rows are too large")
+	}
+	// illegal case 2 (generic functions are in safe mode even with UnexportedSet)
+	{
+		type csventry struct {
+			no int
+		}
+
+		entries, err := LoadAsWith[csventry](strings.NewReader("1\n"), WithUnexported(UnexportedSet))
+		assert.EqualError(t, err, "unexported field no cannot be set in safe mode")
+		assert.Nil(t, entries)
+	}
+}
@@ -0,0 +1,214 @@
+package gotinycsv
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// parallelBatchRows is the number of rows converted at once by a worker.
+const parallelBatchRows = 256
+
+type recordBatch struct {
+	seq     int
+	records [][]string
+	err     error
+}
+
+type convertedBatch struct {
+	seq   int
+	slice reflect.Value
+	// err is the read error, and convErr is the conversion error
+	err     error
+	convErr error
+}
+
+// readBatches reads csv records and sends them to "batches" in batches of parallelBatchRows.
+// A read error is sent as the last batch after the records read before it.
+// Each record is fitted to "width" columns by the column policy of "cfg".
+func readBatches(cr *csv.Reader, topmergin int, maxrows int, cfg *config, width int, explicit bool, batches chan<- recordBatch, done <-chan struct{}) {
+	defer close(batches)
+
+	seq := 0
+	send := func(b recordBatch) bool {
+		b.seq = seq
+		seq++
+		select {
+		case batches <- b:
+			return true
+		case <-done:
+			return false
+		}
+	}
+
+	records := make([][]string, 0, parallelBatchRows)
+	rows := 0
+	for ; ; rows++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if rows < topmergin {
+			continue
+		}
+		if err == nil && maxrows > 0 && rows >= topmergin+maxrows {
+			err = fmt.Errorf("rows are too large")
+		}
+		if err == nil {
+			record, err = fitRecord(cfg.columns, cr, record, width, explicit)
+		}
+		if err != nil {
+			if len(records) > 0 && !send(recordBatch{records: records}) {
+				return
+			}
+			send(recordBatch{err: err})
+			return
+		}
+		records = append(records, record)
+		if len(records) == parallelBatchRows {
+			if !send(recordBatch{records: records}) {
+				return
+			}
+			records = make([][]string, 0, parallelBatchRows)
+		}
+	}
+	if rows <= topmergin {
+		send(recordBatch{err: fmt.Errorf("topmergin is too large")})
+		return
+	}
+	if len(records) > 0 {
+		send(recordBatch{records: records})
+	}
+}
+
+// convertBatch converts "records" into a new slice of "slicet".
+func convertBatch(slicet reflect.Type, plan *decodePlan, records [][]string, cfg *config) (reflect.Value, error) {
+	slice := reflect.New(slicet).Elem()
+	if len(records) == 0 {
+		return slice, nil
+	}
+	if err := ensureSliceCapacity(slice, len(records)); err != nil {
+		return slice, err
+	}
+	for rows, record := range records {
+		if err := plan.setRecord(elemRef(slice, rows), record, cfg); err != nil {
+			return slice, err
+		}
+	}
+	return slice, nil
+}
+
+// Load a CSV converting records on multiple goroutines.
+// Records are read on one goroutine, and converted in batches on "workers" goroutines.
+// If "workers" is 0 or less, runtime.GOMAXPROCS(0) is used.
+// The rows of "out" are in the same order as the csv data, and "out" is replaced by a new slice.
+// The error is the same as the one Load returns: read errors and too large rows take precedence over conversion errors,
+// and the conversion error for the earliest row is returned otherwise. Records are read to the end to find read errors.
+// The elements of "out" must be struct or pointer to struct, and unexported fields are handled in safe mode.
+// The other arguments are the same as Load.
+func LoadParallel(r io.Reader, topmergin int, maxrows int, workers int, out interface{}, ops ...string) error {
+	return LoadParallelWith(r, out, WithSkipRows(topmergin), WithMaxRows(maxrows), WithWorkers(workers), withOps(ops))
+}
+
+// LoadParallelWith loads a CSV converting records on multiple goroutines with options.
+// It is the same as LoadParallel except that the settings are given by "opts".
+func LoadParallelWith(r io.Reader, out interface{}, opts ...Option) error {
+	return loadParallel(r, out, newConfig(opts...))
+}
+
+func loadParallel(r io.Reader, out interface{}, cfg *config) error {
+	if r == nil {
+		return fmt.Errorf("reader is nil")
+	}
+	r, cfg, err := cfg.input(r, true)
+	if err != nil {
+		return err
+	}
+	topmergin, maxrows, workers := cfg.topmergin, cfg.maxrows, cfg.workers
+	refp, err := sliceRefPointer(out)
+	if err != nil {
+		return err
+	}
+	elemt := refp.Type().Elem()
+	if isMapElem(elemt) {
+		return fmt.Errorf("elements of slice must be struct")
+	}
+	plan, err := checkedPlan(elemt, cfg)
+	if err != nil {
+		return err
+	}
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+
+	batches := make(chan recordBatch, workers)
+	results := make(chan convertedBatch, workers)
+
+	go readBatches(cfg.newReader(r), topmergin, maxrows, cfg, len(plan.columns), plan.explicit, batches, done)
+
+	// set after a conversion error, then the rest of the records are only read
+	var failed atomic.Bool
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for b := range batches {
+				res := convertedBatch{seq: b.seq, err: b.err}
+				if res.err == nil && !failed.Load() {
+					res.slice, res.convErr = convertBatch(refp.Type(), plan, b.records, cfg)
+				}
+				select {
+				case results <- res:
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// merge batches in input order
+	merged := reflect.MakeSlice(refp.Type(), 0, 0)
+	pending := map[int]convertedBatch{}
+	next := 0
+	var convErr error
+	for res := range results {
+		pending[res.seq] = res
+		for {
+			b, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			next++
+			if b.err != nil {
+				return b.err
+			}
+			if convErr == nil && b.convErr != nil {
+				convErr = b.convErr
+				failed.Store(true)
+			}
+			if convErr == nil {
+				merged = reflect.AppendSlice(merged, b.slice)
+			}
+		}
+	}
+	if convErr != nil {
+		return convErr
+	}
+	refp.Set(merged)
+
+	return nil
+}
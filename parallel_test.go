@@ -0,0 +1,151 @@
+package gotinycsv
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_LoadParallel(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString("No,Name,Height\n")
+	for i := 0; i < 2000; i++ {
+		fmt.Fprintf(&sb, "%d,name%d,%d.5\n", i, i, i)
+	}
+	csv := sb.String()
+
+	type csventry struct {
+		No     int
+		Name   string
+		Height float64
+	}
+
+	// normal case 1 (same result as Load)
+	{
+		expected := []csventry{}
+		err := Load(strings.NewReader(csv), 1, 0, &expected)
+		assert.NoError(t, err)
+
+		entries := []csventry{}
+		err = LoadParallel(strings.NewReader(csv), 1, 0, 4, &entries)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, entries)
+	}
+	// normal case 2 (pointer elements, default workers)
+	{
+		entries := []*csventry{}
+		err := LoadParallel(strings.NewReader(csv), 1, 0, 0, &entries)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2000, len(entries))
+		for i, e := range entries {
+			assert.Equal(t, i, e.No)
+		}
+	}
+	// illegal case 1 (the error of the earliest row is returned)
+	{
+		err := RegisterEnum(map[string]testStatus{"active": testStatusActive}, false)
+		assert.NoError(t, err)
+
+		var sb strings.Builder
+		for i := 0; i < 2000; i++ {
+			switch i {
+			case 700, 1500:
+				fmt.Fprintf(&sb, "%d,unknown%d\n", i, i)
+			default:
+				fmt.Fprintf(&sb, "%d,active\n", i)
+			}
+		}
+		type csventry struct {
+			No     int
+			Status testStatus
+		}
+
+		for i := 0; i < 10; i++ {
+			entries := []csventry{}
+			err = LoadParallel(strings.NewReader(sb.String()), 0, 0, 8, &entries)
+
+			assert.EqualError(t, err, "unknown label for gotinycsv.testStatus: unknown700")
+		}
+	}
+	// illegal case 2 (read errors and too large rows precede conversion errors as Load)
+	{
+		var sb strings.Builder
+		sb.WriteString("1,zzz\n")
+		for i := 2; i < 1002; i++ {
+			fmt.Fprintf(&sb, "%d,active\n", i)
+		}
+		sb.WriteString("1002,\"active\n")
+		type csventry struct {
+			No     int
+			Status testStatus
+		}
+
+		entries := []csventry{}
+		want := Load(strings.NewReader(sb.String()), 0, 0, &entries)
+		assert.EqualError(t, want, "parse error on line 1002, column 14: extraneous or missing \" in quoted-field")
+		for i := 0; i < 10; i++ {
+			assert.EqualError(t, LoadParallel(strings.NewReader(sb.String()), 0, 0, 8, &entries), want.Error())
+		}
+
+		want = Load(strings.NewReader(sb.String()), 0, 5, &entries)
+		assert.EqualError(t, want, "rows are too large")
+		assert.EqualError(t, LoadParallel(strings.NewReader(sb.String()), 0, 5, 8, &entries), want.Error())
+	}
+	// illegal case 3 (too large rows)
+	{
+		entries := []csventry{}
+		err := LoadParallel(strings.NewReader(csv), 1, 1000, 4, &entries)
+
+		assert.EqualError(t, err, "rows are too large")
+	}
+	// illegal case 4 (too large topmergin)
+	{
+		entries := []csventry{}
+		err := LoadParallel(strings.NewReader(csv), 3000, 0, 4, &entries)
+
+		assert.EqualError(t, err, "topmergin is too large")
+	}
+	// illegal case 5 (map elements)
+	{
+		entries := []map[string]string{}
+		err := LoadParallel(strings.NewReader(csv), 1, 0, 4, &entries)
+
+		assert.EqualError(t, err, "elements of slice must be struct")
+	}
+}
+
+func Benchmark_LoadParallel(b *testing.B) {
+	var sb strings.Builder
+	for i := 0; i < 100000; i++ {
+		fmt.Fprintf(&sb, "%d,name%d,%d.5,2011.12.12\n", i, i, i)
+	}
+	csv := sb.String()
+
+	type csventry struct {
+		No     int
+		Name   string
+		Height float64
+		Birth  string
+	}
+
+	b.Run("Load", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			entries := []csventry{}
+			if err := Load(strings.NewReader(csv), 0, 0, &entries); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("LoadParallel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			entries := []csventry{}
+			if err := LoadParallel(strings.NewReader(csv), 0, 0, 0, &entries); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,234 @@
+package gotinycsv
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// CloneStrings makes string fields hold a copy of only their own bytes,
+// instead of sharing the string backing the whole csv record.
+// It costs an allocation per non-empty string field, but the rest of the record can be garbage collected.
+// The "clone" tag option enables it per field, and WithCloneStrings overrides it per call.
+var CloneStrings = false
+
+// setter sets a csv field into the structure field at "p".
+type setter func(p unsafe.Pointer, timelayout, v string) error
+
+// fieldPlan is the compiled settings of a structure field.
+type fieldPlan struct {
+	name   string
+	offset uintptr
+	// exported and blank are used to skip fields in safe mode
+	exported bool
+	blank    bool
+	spec     fieldSpec
+	set      setter
+	// str is true for string fields, which may be cloned or interned
+	str bool
+}
+
+// decodePlan is the compiled settings of a struct type, which replaces
+// the per-cell references of eachStructFieldRefs and the per-cell dispatch of setEntityViaRef.
+type decodePlan struct {
+	fields []fieldPlan
+	// columns is the index of the field bound to each column, or -1
+	columns []int
+	// explicit is true if columns are bound by the index or "-" tag, then unbound columns are ignored
+	explicit bool
+}
+
+var plans sync.Map // reflect.Type -> *decodePlan
+
+// planFor returns the cached decode plan of the struct type "t" (or pointer to struct), compiling it at the first call.
+// Unsupported field types are reported by check, since they may be skipped in safe mode.
+func planFor(t reflect.Type) (*decodePlan, error) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if p, ok := plans.Load(t); ok {
+		return p.(*decodePlan), nil
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("elements of slice must be struct")
+	}
+	specs, err := structFieldSpecs(t)
+	if err != nil {
+		return nil, err
+	}
+	columns, explicit, err := columnBindings(specs)
+	if err != nil {
+		return nil, err
+	}
+	p := &decodePlan{fields: make([]fieldPlan, t.NumField()), columns: columns, explicit: explicit}
+	for i := range p.fields {
+		f := t.Field(i)
+		set, _ := setterFor(f.Type)
+		_, enum := lookupEnum(f.Type)
+		p.fields[i] = fieldPlan{
+			name:     f.Name,
+			offset:   f.Offset,
+			exported: f.IsExported(),
+			blank:    f.Name == "_",
+			spec:     specs[i],
+			set:      set,
+			str:      f.Type.Kind() == reflect.String && !enum,
+		}
+	}
+	plans.Store(t, p)
+	return p, nil
+}
+
+// setterFor returns the setter specialized for "t".
+// It must be consistent with setEntityViaRef.
+func setterFor(t reflect.Type) (setter, bool) {
+	if et, ok := lookupEnum(t); ok {
+		return func(p unsafe.Pointer, _, v string) error {
+			return et.set(reflect.NewAt(t, p).Elem(), v)
+		}, true
+	}
+	switch t.Kind() {
+	case reflect.Int:
+		return func(p unsafe.Pointer, _, v string) error {
+			iv, _ := strconv.Atoi(v)
+			*(*int)(p) = iv
+			return nil
+		}, true
+	case reflect.Int8:
+		return func(p unsafe.Pointer, _, v string) error {
+			iv, _ := strconv.Atoi(v)
+			*(*int8)(p) = int8(iv)
+			return nil
+		}, true
+	case reflect.Int16:
+		return func(p unsafe.Pointer, _, v string) error {
+			iv, _ := strconv.Atoi(v)
+			*(*int16)(p) = int16(iv)
+			return nil
+		}, true
+	case reflect.Int32:
+		return func(p unsafe.Pointer, _, v string) error {
+			iv, _ := strconv.Atoi(v)
+			*(*int32)(p) = int32(iv)
+			return nil
+		}, true
+	case reflect.Int64:
+		return func(p unsafe.Pointer, _, v string) error {
+			iv, _ := strconv.Atoi(v)
+			*(*int64)(p) = int64(iv)
+			return nil
+		}, true
+	case reflect.Float32:
+		return func(p unsafe.Pointer, _, v string) error {
+			fv, _ := strconv.ParseFloat(v, 32)
+			*(*float32)(p) = float32(fv)
+			return nil
+		}, true
+	case reflect.Float64:
+		return func(p unsafe.Pointer, _, v string) error {
+			fv, _ := strconv.ParseFloat(v, 64)
+			*(*float64)(p) = fv
+			return nil
+		}, true
+	case reflect.String:
+		return func(p unsafe.Pointer, _, v string) error {
+			*(*string)(p) = v
+			return nil
+		}, true
+	case reflect.Struct:
+		if t != timeType {
+			return nil, false
+		}
+		return func(p unsafe.Pointer, timelayout, v string) error {
+			tv, _ := time.Parse(timelayout, v)
+			*(*time.Time)(p) = tv
+			return nil
+		}, true
+	}
+	return nil, false
+}
+
+// checkedPlan returns the decode plan of "t" checked against the settings of "cfg".
+func checkedPlan(t reflect.Type, cfg *config) (*decodePlan, error) {
+	p, err := planFor(t)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.check(cfg.unexported); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// setField sets the csv field "v" into the "i"-th field of the addressable struct "ref".
+// In safe mode the field is written through reflect, otherwise through an unsafe pointer.
+func (p *decodePlan) setField(ref reflect.Value, i int, v string, cfg *config) error {
+	f := &p.fields[i]
+	if f.skipped(cfg.unexported) {
+		return nil
+	}
+	v = f.spec.trimmed(v, cfg)
+	if f.str {
+		interned := false
+		if f.spec.interned(cfg) {
+			v, interned = cfg.interns.table(f).intern(v, cfg.internLimit)
+		}
+		if !interned && f.spec.cloned(cfg) {
+			v = strings.Clone(v)
+		}
+	}
+	if cfg.unexported != UnexportedSet {
+		return setEntityViaRef(ref.Field(i), cfg.timelayout, v)
+	}
+	return f.set(unsafe.Add(unsafe.Pointer(ref.UnsafeAddr()), f.offset), cfg.timelayout, v)
+}
+
+// field returns the index of the field bound to the "c"-th column, or -1.
+func (p *decodePlan) field(c int) int {
+	if c >= len(p.columns) {
+		return -1
+	}
+	return p.columns[c]
+}
+
+// checkColumns reports an error if a record of "n" columns has columns bound to no field.
+func (p *decodePlan) checkColumns(n int) error {
+	if !p.explicit && len(p.columns) < n {
+		return fmt.Errorf("number of fields in the defined structure may not match the number of fields in the CSV.")
+	}
+	return nil
+}
+
+// setRecord sets the csv record into the addressable struct "ref".
+func (p *decodePlan) setRecord(ref reflect.Value, record []string, cfg *config) error {
+	if err := p.checkColumns(len(record)); err != nil {
+		return err
+	}
+	for c, v := range record {
+		i := p.field(c)
+		if i < 0 {
+			continue
+		}
+		if err := p.setField(ref, i, v, cfg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// elemRef returns the addressable struct of the "i"-th element of the slice "ref".
+// A nil pointer element is allocated.
+func elemRef(ref reflect.Value, i int) reflect.Value {
+	elem := ref.Index(i)
+	if elem.Kind() == reflect.Ptr {
+		if elem.IsNil() {
+			elem.Set(reflect.New(elem.Type().Elem()))
+		}
+		return elem.Elem()
+	}
+	return elem
+}
@@ -0,0 +1,154 @@
+package gotinycsv
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_planFor(t *testing.T) {
+	// normal case 1 (cached per type)
+	{
+		type teststruct struct {
+			a int8
+			b string `csv:",trim=none"`
+			c time.Time
+		}
+
+		plan, err := planFor(reflect.TypeOf(teststruct{}))
+		assert.NoError(t, err)
+		assert.Equal(t, 3, len(plan.fields))
+		assert.Equal(t, unsafe.Offsetof(teststruct{}.b), plan.fields[1].offset)
+		assert.True(t, plan.fields[1].spec.hasTrim)
+		pplan, err := planFor(reflect.TypeOf(&teststruct{}))
+		assert.NoError(t, err)
+		assert.Same(t, plan, pplan)
+	}
+	// normal case 2 (setters are consistent with setEntityViaRef)
+	{
+		type teststruct struct {
+			a int
+			b int8
+			c int16
+			d int32
+			e int64
+			f float32
+			g float64
+			h string
+			i time.Time
+		}
+
+		record := []string{"300", "300", "70000", "-5", "9000000000", "1.25", "2.5", " x ", "2022.01.02"}
+		expected := []teststruct{{}}
+		refs, err := eachStructFieldRefs(reflect.ValueOf(expected))
+		assert.NoError(t, err)
+		for i, v := range record {
+			assert.NoError(t, setEntityViaRef(refs[0][i], "2006.01.02", v))
+		}
+
+		actual := teststruct{}
+		plan, err := planFor(reflect.TypeOf(actual))
+		assert.NoError(t, err)
+		for i, v := range record {
+			f := plan.fields[i]
+			assert.NoError(t, f.set(unsafe.Add(unsafe.Pointer(&actual), f.offset), "2006.01.02", v))
+		}
+		assert.Equal(t, expected[0], actual)
+	}
+	// normal case 3 (registering an enum recompiles plans)
+	{
+		type testLevel int
+		type teststruct struct {
+			level testLevel
+		}
+
+		entries := []teststruct{}
+		assert.NoError(t, Load(strings.NewReader("high\n"), 0, 1, &entries))
+		assert.Equal(t, testLevel(0), entries[0].level)
+		assert.NoError(t, RegisterEnum(map[string]testLevel{"high": 2}, false))
+		assert.NoError(t, Load(strings.NewReader("high\n"), 0, 1, &entries))
+		assert.Equal(t, testLevel(2), entries[0].level)
+	}
+	// illegal case 1 (not struct)
+	{
+		plan, err := planFor(reflect.TypeOf(0))
+		assert.EqualError(t, err, "elements of slice must be struct")
+		assert.Nil(t, plan)
+	}
+	// illegal case 2 (unsupported type)
+	{
+		type teststruct struct {
+			a []int
+		}
+
+		plan, err := planFor(reflect.TypeOf(teststruct{}))
+		assert.NoError(t, err)
+		assert.EqualError(t, plan.check(UnexportedSet), "Unsupported types are used in structure fields")
+		plan, err = checkedPlan(reflect.TypeOf(teststruct{}), newConfig(withTraditional()))
+		assert.EqualError(t, err, "Unsupported types are used in structure fields")
+		assert.Nil(t, plan)
+	}
+}
+
+type benchentry struct {
+	No     int
+	Name   string
+	Age    int64
+	Height float64
+	Weight float32
+	Birth  time.Time
+}
+
+func benchRecords(n int) [][]string {
+	records := make([][]string, n)
+	for i := range records {
+		records[i] = []string{fmt.Sprint(i), "name", "41", "74.5", "170", "1999.1.1"}
+	}
+	return records
+}
+
+// Benchmark_decode compares the per-cell references of eachStructFieldRefs with the cached plan.
+func Benchmark_decode(b *testing.B) {
+	records := benchRecords(10000)
+
+	b.Run("refs", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			entries := make([]benchentry, len(records))
+			refs, err := eachStructFieldRefs(reflect.ValueOf(entries))
+			if err != nil {
+				b.Fatal(err)
+			}
+			specs, _ := structFieldSpecs(reflect.TypeOf(benchentry{}))
+			cfg := newConfig()
+			for rows, record := range records {
+				for cols, v := range record {
+					if err := setEntityViaRef(refs[rows][cols], "2006.1.2", specs[cols].trimmed(v, cfg)); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		}
+	})
+	b.Run("plan", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			entries := make([]benchentry, len(records))
+			cfg := newConfig()
+			plan, err := checkedPlan(reflect.TypeOf(benchentry{}), cfg)
+			if err != nil {
+				b.Fatal(err)
+			}
+			for rows, record := range records {
+				if err := plan.setRecord(reflect.ValueOf(&entries[rows]).Elem(), record, cfg); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+}
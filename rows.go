@@ -0,0 +1,63 @@
+package gotinycsv
+
+import (
+	"io"
+	"iter"
+)
+
+// Positioned is a row with its position in the csv data.
+type Positioned[T any] struct {
+	Pos Position
+	Row T
+}
+
+// Rows returns an iterator over the rows of a CSV decoded into T.
+// An error is yielded once with the zero value of T, then the iteration ends.
+// Breaking the loop stops reading the rest of the csv data.
+// The arguments are the same as NewDecoder, but it is in safe mode like LoadAs.
+//
+//	for row, err := range gotinycsv.Rows[Entry](r, topmergin) {
+//		if err != nil {
+//			return err
+//		}
+//	}
+func Rows[T any](r io.Reader, topmergin int, ops ...string) iter.Seq2[T, error] {
+	return RowsWith[T](r, WithSkipRows(topmergin), withOps(ops))
+}
+
+// RowsWith is the same as Rows except that the settings are given by "opts".
+func RowsWith[T any](r io.Reader, opts ...Option) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for p, err := range RowsWithPositionWith[T](r, opts...) {
+			if !yield(p.Row, err) {
+				return
+			}
+		}
+	}
+}
+
+// RowsWithPosition is the same as Rows, but each row is yielded with its position.
+func RowsWithPosition[T any](r io.Reader, topmergin int, ops ...string) iter.Seq2[Positioned[T], error] {
+	return RowsWithPositionWith[T](r, WithSkipRows(topmergin), withOps(ops))
+}
+
+// RowsWithPositionWith is the same as RowsWithPosition except that the settings are given by "opts".
+// The settings are built at each start of the iteration, so the interned strings are not shared between iterations.
+func RowsWithPositionWith[T any](r io.Reader, opts ...Option) iter.Seq2[Positioned[T], error] {
+	return func(yield func(Positioned[T], error) bool) {
+		dec := newDecoder(r, newConfig(opts...).safe())
+		for dec.Next() {
+			var row T
+			if err := dec.Decode(&row); err != nil {
+				yield(Positioned[T]{Pos: dec.Position()}, err)
+				return
+			}
+			if !yield(Positioned[T]{Pos: dec.Position(), Row: row}, nil) {
+				return
+			}
+		}
+		if err := dec.Err(); err != nil {
+			yield(Positioned[T]{}, err)
+		}
+	}
+}
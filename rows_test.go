@@ -0,0 +1,95 @@
+package gotinycsv
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Rows(t *testing.T) {
+	csv := `No,Name
+1,Alex
+2,Bert
+3,Carl
+`
+	type csventry struct {
+		No   int
+		Name string
+	}
+
+	// normal case 1
+	{
+		entries := []csventry{}
+		for row, err := range Rows[csventry](strings.NewReader(csv), 1) {
+			assert.NoError(t, err)
+			entries = append(entries, row)
+		}
+
+		assert.Equal(t, []csventry{{1, "Alex"}, {2, "Bert"}, {3, "Carl"}}, entries)
+	}
+	// normal case 2 (break does not read the rest)
+	{
+		var sb strings.Builder
+		for i := 0; i < 10000; i++ {
+			sb.WriteString("1,Alex\n")
+		}
+		r := strings.NewReader(sb.String())
+		rows := 0
+		for row, err := range Rows[csventry](r, 0) {
+			assert.NoError(t, err)
+			assert.Equal(t, "Alex", row.Name)
+			rows++
+			break
+		}
+		assert.Equal(t, 1, rows)
+		assert.NotEqual(t, 0, r.Len())
+	}
+	// normal case 3 (with position)
+	{
+		positions := []Position{}
+		for p, err := range RowsWithPosition[*csventry](strings.NewReader(csv), 1) {
+			assert.NoError(t, err)
+			assert.NotNil(t, p.Row)
+			positions = append(positions, p.Pos)
+		}
+
+		assert.Equal(t, []Position{{0, 2}, {1, 3}, {2, 4}}, positions)
+	}
+	// normal case 4 (with position and options, interned strings are not shared between iterations)
+	{
+		r := strings.NewReader("1,Alex\n2,Alex\n")
+		rows := RowsWithPositionWith[csventry](r, WithIntern(true))
+		names := []string{}
+		for i := 0; i < 2; i++ {
+			r.Seek(0, io.SeekStart)
+			for p, err := range rows {
+				assert.NoError(t, err)
+				assert.Equal(t, p.Row.No, p.Pos.Row+1)
+				names = append(names, p.Row.Name)
+			}
+		}
+
+		assert.Equal(t, []string{"Alex", "Alex", "Alex", "Alex"}, names)
+		assert.Same(t, unsafe.StringData(names[0]), unsafe.StringData(names[1]))
+		assert.NotSame(t, unsafe.StringData(names[0]), unsafe.StringData(names[2]))
+	}
+	// illegal case (collapse format)
+	{
+		csv := `1,Alex
+2
+3,Carl
+`
+		rows := 0
+		var lasterr error
+		for _, err := range Rows[csventry](strings.NewReader(csv), 0) {
+			rows++
+			lasterr = err
+		}
+
+		assert.Equal(t, 2, rows)
+		assert.EqualError(t, lasterr, "record on line 2: wrong number of fields")
+	}
+}
@@ -0,0 +1,49 @@
+package gotinycsv
+
+import "fmt"
+
+// UnexportedPolicy specifies how unexported structure fields are handled.
+type UnexportedPolicy int
+
+const (
+	// UnexportedSet sets all fields including unexported ones via unsafe pointers.
+	// Blank "_" fields are also parsed. This is the traditional behavior of Load and LoadVertically.
+	UnexportedSet UnexportedPolicy = iota
+	// UnexportedSkip sets only exported fields, and skips the columns bound to unexported fields.
+	UnexportedSkip
+	// UnexportedError sets only exported fields, and emits an error if the struct has unexported fields.
+	UnexportedError
+)
+
+// DefaultUnexported is the policy for unexported fields of Load and LoadVertically.
+// The other functions are in safe mode, that is UnexportedError unless DefaultUnexported is UnexportedSkip.
+// The functions taking "out interface{}" write unexported fields only if WithUnexported(UnexportedSet) is given,
+// and the generic functions (LoadAs, LoadVerticallyAs, ForEach and Rows) never do.
+// In safe mode (UnexportedSkip and UnexportedError), blank "_" fields are skipped without parsing,
+// and structure fields are written only through reflect, never through unsafe pointers.
+var DefaultUnexported = UnexportedSet
+
+// skipped reports whether the field is skipped under "policy".
+func (f *fieldPlan) skipped(policy UnexportedPolicy) bool {
+	return policy != UnexportedSet && (f.blank || !f.exported)
+}
+
+// check reports an error if the struct cannot be loaded under "policy".
+func (p *decodePlan) check(policy UnexportedPolicy) error {
+	for i := range p.fields {
+		f := &p.fields[i]
+		if f.spec.skip {
+			continue
+		}
+		if f.skipped(policy) {
+			if !f.blank && policy == UnexportedError {
+				return fmt.Errorf("unexported field %s cannot be set in safe mode", f.name)
+			}
+			continue
+		}
+		if f.set == nil {
+			return fmt.Errorf("Unsupported types are used in structure fields")
+		}
+	}
+	return nil
+}
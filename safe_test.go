@@ -0,0 +1,178 @@
+package gotinycsv
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_UnexportedPolicy(t *testing.T) {
+	csv := `No,Memo,Name,Birth
+1,x,Alex,1999.01.01
+2,y,Bert,2001.02.02
+`
+	// normal case 1 (UnexportedSet sets all fields)
+	{
+		type csventry struct {
+			No    int
+			memo  string
+			Name  string
+			Birth time.Time
+		}
+
+		entries := []csventry{}
+		assert.NoError(t, Load(strings.NewReader(csv), 1, 10, &entries))
+		assert.Equal(t, "y", entries[1].memo)
+	}
+	// normal case 2 (UnexportedSkip skips unexported and blank fields without parsing)
+	{
+		type csventry struct {
+			No    int
+			_     []int
+			name  string
+			Birth time.Time
+		}
+
+		DefaultUnexported = UnexportedSkip
+		entries := []csventry{}
+		err := Load(strings.NewReader(csv), 1, 10, &entries)
+		DefaultUnexported = UnexportedSet
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(entries))
+		assert.Equal(t, 2, entries[1].No)
+		assert.Equal(t, "", entries[1].name)
+		assert.Equal(t, "2001-02-02 00:00:00 +0000 UTC", entries[1].Birth.String())
+	}
+	// normal case 3 (generic functions are in safe mode, blank fields are skipped)
+	{
+		type csventry struct {
+			No    int
+			_     string
+			Name  string
+			Birth time.Time
+		}
+
+		entries, err := LoadAs[csventry](strings.NewReader(csv), 1, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, "Alex", entries[0].Name)
+
+		rows := 0
+		assert.NoError(t, ForEach(strings.NewReader(csv), 1, func(row *csventry, pos Position) error {
+			rows++
+			return nil
+		}))
+		assert.Equal(t, 2, rows)
+	}
+	// normal case 4 (LoadColumns)
+	{
+		type csvcolumns struct {
+			No    []int
+			memo  []string
+			Name  []string
+			Birth []time.Time
+		}
+
+		DefaultUnexported = UnexportedSkip
+		var columns csvcolumns
+		err := LoadColumns(strings.NewReader(csv), 1, 10, &columns)
+		DefaultUnexported = UnexportedSet
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 2}, columns.No)
+		assert.Nil(t, columns.memo)
+		assert.Equal(t, []string{"Alex", "Bert"}, columns.Name)
+	}
+	// normal case 5 (only Load and LoadVertically write unexported fields by default)
+	{
+		type csventry struct {
+			No    int
+			memo  string
+			Name  string
+			Birth time.Time
+		}
+
+		entries := []csventry{}
+		assert.NoError(t, Dialect{}.Load(strings.NewReader(csv), 1, 10, &entries))
+		assert.Equal(t, "y", entries[1].memo)
+
+		err := LoadWith(strings.NewReader(csv), &entries, WithSkipRows(1))
+		assert.EqualError(t, err, "unexported field memo cannot be set in safe mode")
+		err = LoadParallel(strings.NewReader(csv), 1, 10, 2, &entries)
+		assert.EqualError(t, err, "unexported field memo cannot be set in safe mode")
+
+		entries = []csventry{}
+		assert.NoError(t, LoadParallelWith(strings.NewReader(csv), &entries, WithSkipRows(1), WithUnexported(UnexportedSet)))
+		assert.Equal(t, "y", entries[1].memo)
+
+		DefaultUnexported = UnexportedSkip
+		entries = []csventry{}
+		err = LoadWith(strings.NewReader(csv), &entries, WithSkipRows(1))
+		DefaultUnexported = UnexportedSet
+
+		assert.NoError(t, err)
+		assert.Equal(t, "", entries[1].memo)
+	}
+	// illegal case 1 (generic functions reject unexported fields)
+	{
+		type csventry struct {
+			No    int
+			memo  string
+			Name  string
+			Birth time.Time
+		}
+
+		entries, err := LoadAs[csventry](strings.NewReader(csv), 1, 10)
+		assert.EqualError(t, err, "unexported field memo cannot be set in safe mode")
+		assert.Nil(t, entries)
+
+		for _, err := range Rows[csventry](strings.NewReader(csv), 1) {
+			assert.EqualError(t, err, "unexported field memo cannot be set in safe mode")
+		}
+	}
+	// illegal case 2 (UnexportedError)
+	{
+		type csventry struct {
+			No    int
+			memo  string
+			Name  string
+			Birth time.Time
+		}
+
+		DefaultUnexported = UnexportedError
+		entries := []csventry{}
+		err := Load(strings.NewReader(csv), 1, 10, &entries)
+		var columns struct {
+			no []int
+		}
+		cerr := LoadColumns(strings.NewReader(csv), 1, 10, &columns)
+		DefaultUnexported = UnexportedSet
+
+		assert.EqualError(t, err, "unexported field memo cannot be set in safe mode")
+		assert.EqualError(t, cerr, "unexported field no cannot be set in safe mode")
+	}
+	// illegal case 3 (unexported key field in safe mode)
+	{
+		type csventry struct {
+			no   int `csv:",key"`
+			Name string
+		}
+
+		m := map[int]csventry{}
+		err := LoadWith(strings.NewReader("1,Alex\n2,Bert\n"), &m, WithUnexported(UnexportedSkip))
+		assert.EqualError(t, err, "unexported field no cannot be set in safe mode")
+
+		assert.NoError(t, LoadWith(strings.NewReader("1,Alex\n2,Bert\n"), &m, WithUnexported(UnexportedSet)))
+		assert.Equal(t, map[int]csventry{1: {1, "Alex"}, 2: {2, "Bert"}}, m)
+
+		type exported struct {
+			No   int `csv:",key"`
+			Name string
+		}
+		em := map[int]exported{}
+		assert.NoError(t, LoadWith(strings.NewReader("1,Alex\n2,Bert\n"), &em, WithUnexported(UnexportedError)))
+		assert.Equal(t, map[int]exported{1: {1, "Alex"}, 2: {2, "Bert"}}, em)
+	}
+}
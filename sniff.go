@@ -0,0 +1,349 @@
+package gotinycsv
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultSniffSize is the number of bytes inspected by Sniff and WithAuto when the size is 0 or less.
+const DefaultSniffSize = 64 << 10
+
+// sniffDelimiters are the delimiters detected by Sniff, in order of priority.
+var sniffDelimiters = []rune{',', '\t', ';', '|'}
+
+// Sniffed is the format of a CSV detected by Sniff.
+type Sniffed struct {
+	// Dialect has the detected Comma and LazyQuotes, and can be used by its methods or WithDialect.
+	Dialect Dialect
+	// Quoted is true if quoted fields are found.
+	Quoted bool
+	// Header is true if the first row seems to be a header, that is topmergin should be 1.
+	Header bool
+	// Encoding is the detected character encoding. The data of a byte order mark is UTF-8 after transcoding.
+	Encoding Encoding
+	// LineEnding is "\n", "\r\n" or "\r". It is "\n" if no line break is found.
+	LineEnding string
+}
+
+// Sniff detects the format of a CSV from the first "size" bytes of "r".
+// If "size" is 0 or less, DefaultSniffSize is used.
+// The returned reader reads the whole data of "r" including the inspected bytes, without a byte order mark,
+// and decoded from the detected encoding into UTF-8.
+// If LineEnding is "\r", which encoding/csv does not support, line breaks of the returned reader are converted to "\n".
+//
+//	s, r, err := gotinycsv.Sniff(r, 0)
+//	topmergin := 0
+//	if s.Header {
+//		topmergin = 1
+//	}
+//	err = s.Dialect.Load(r, topmergin, maxrows, &out)
+func Sniff(r io.Reader, size int) (Sniffed, io.Reader, error) {
+	if r == nil {
+		return Sniffed{}, nil, fmt.Errorf("reader is nil")
+	}
+	r, bom := skipBOM(r)
+	return sniff(r, size, 0, !bom)
+}
+
+// sniff detects the format of "r". The encoding is detected if "detect" is true, otherwise it must be UTF-8.
+func sniff(r io.Reader, size int, comment rune, detect bool) (Sniffed, io.Reader, error) {
+	if r == nil {
+		return Sniffed{}, nil, fmt.Errorf("reader is nil")
+	}
+	if size <= 0 {
+		size = DefaultSniffSize
+	}
+	buf := make([]byte, size)
+	n, err := io.ReadFull(r, buf)
+	truncated := err == nil
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		err = nil
+	}
+	if err != nil {
+		return Sniffed{}, nil, err
+	}
+	buf = buf[:n]
+
+	data := buf
+	if truncated {
+		// a character cut at the end must not make the encoding invalid
+		if i := bytes.LastIndexByte(data, '\n'); i >= 0 {
+			data = data[:i+1]
+		}
+	}
+	enc := UTF8
+	if detect {
+		enc = sniffEncoding(data)
+	}
+	if enc != UTF8 {
+		data, _ = io.ReadAll(enc.NewReader(bytes.NewReader(buf)))
+	} else {
+		data = buf
+	}
+
+	s := sniffBytes(data, truncated, comment)
+	s.Encoding = enc
+	all := enc.NewReader(io.MultiReader(bytes.NewReader(buf), r))
+	if s.LineEnding == "\r" {
+		all = &crReader{r: all}
+	}
+	return s, all, nil
+}
+
+// sniffBytes detects the format of "data". If "truncated" is true, the last incomplete line is ignored.
+func sniffBytes(data []byte, truncated bool, comment rune) Sniffed {
+	s := Sniffed{Dialect: Dialect{Comma: ','}, LineEnding: sniffLineEnding(data)}
+	if s.LineEnding == "\r" {
+		data = bytes.ReplaceAll(data, []byte("\r"), []byte("\n"))
+	}
+	if truncated {
+		if i := bytes.LastIndexByte(data, '\n'); i >= 0 {
+			data = data[:i+1]
+		}
+	}
+
+	// the delimiter giving the most records of the same number of fields wins,
+	// and the one giving more fields wins a tie
+	var records [][]string
+	bestRatio, bestFields := 0.0, 1
+	for _, comma := range sniffDelimiters {
+		recs, _ := sniffRecords(data, comma, comment, true)
+		fields, count := modeFields(recs)
+		if fields < 2 {
+			continue
+		}
+		ratio := float64(count) / float64(len(recs))
+		if ratio > bestRatio || (ratio == bestRatio && fields > bestFields) {
+			s.Dialect.Comma, bestRatio, bestFields, records = comma, ratio, fields, recs
+		}
+	}
+
+	s.Quoted = hasQuotedField(data, s.Dialect.Comma)
+	if s.Quoted {
+		_, err := sniffRecords(data, s.Dialect.Comma, comment, false)
+		var pe *csv.ParseError
+		if errors.As(err, &pe) {
+			// an unterminated quote at the end of truncated data is not an error of the data
+			incomplete := truncated && pe.Err == csv.ErrQuote && pe.Line >= bytes.Count(data, []byte("\n"))
+			if (pe.Err == csv.ErrBareQuote || pe.Err == csv.ErrQuote) && !incomplete {
+				s.Dialect.LazyQuotes = true
+			}
+		}
+	}
+
+	s.Header = sniffHeader(records)
+	return s
+}
+
+// sniffRecords reads the records of "data" until an error.
+func sniffRecords(data []byte, comma rune, comment rune, lazy bool) ([][]string, error) {
+	cr := csv.NewReader(bytes.NewReader(data))
+	cr.Comma = comma
+	cr.Comment = comment
+	cr.LazyQuotes = lazy
+	cr.FieldsPerRecord = -1
+	var records [][]string
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return records, err
+		}
+		records = append(records, record)
+	}
+}
+
+// modeFields returns the most frequent number of fields of "records" and its count.
+func modeFields(records [][]string) (fields int, count int) {
+	counts := map[int]int{}
+	for _, record := range records {
+		counts[len(record)]++
+	}
+	for f, c := range counts {
+		if c > count || (c == count && f > fields) {
+			fields, count = f, c
+		}
+	}
+	return fields, count
+}
+
+func sniffLineEnding(data []byte) string {
+	i := bytes.IndexByte(data, '\n')
+	switch {
+	case i > 0 && data[i-1] == '\r':
+		return "\r\n"
+	case i < 0 && bytes.IndexByte(data, '\r') >= 0:
+		return "\r"
+	}
+	return "\n"
+}
+
+// hasQuotedField reports whether a field of "data" starts with a quote.
+func hasQuotedField(data []byte, comma rune) bool {
+	for i, b := range data {
+		if b == '"' && (i == 0 || rune(data[i-1]) == comma || data[i-1] == '\n' || data[i-1] == '\r') {
+			return true
+		}
+	}
+	return false
+}
+
+// sniffHeader reports whether the first record seems to be a header.
+// Each column votes for a header if the other records are all numbers or dates but the first one is not,
+// and against it if the first one is also a number or a date, or appears in the other records.
+// A column of texts votes for a header if at least two of the other records have the same character class or length
+// but the first one does not.
+func sniffHeader(records [][]string) bool {
+	if len(records) < 2 {
+		return false
+	}
+	votes := 0
+	for c, h := range records[0] {
+		h = strings.TrimSpace(h)
+		if h == "" {
+			continue
+		}
+		typed, total, same := 0, 0, false
+		class, length := -1, -1
+		for _, record := range records[1:] {
+			if c >= len(record) {
+				continue
+			}
+			v := strings.TrimSpace(record[c])
+			if v == "" {
+				continue
+			}
+			total++
+			if isTypedValue(v) {
+				typed++
+			}
+			if v == h {
+				same = true
+			}
+			// -2 means the values differ
+			if k := textClass(v); class == -1 || class == k {
+				class = k
+			} else {
+				class = -2
+			}
+			if l := utf8.RuneCountInString(v); length == -1 || length == l {
+				length = l
+			} else {
+				length = -2
+			}
+		}
+		switch {
+		case total == 0:
+		case same || isTypedValue(h):
+			votes--
+		case typed == total:
+			votes++
+		case typed > 0:
+		// a single value does not show a pattern of the column
+		case total < 2:
+		case class >= 0 && class != textClass(h), length >= 0 && length != utf8.RuneCountInString(h):
+			votes++
+		}
+	}
+	return votes > 0
+}
+
+// textClass returns the set of the kinds of characters in "v": lower case, upper case, digit, space and the others.
+func textClass(v string) int {
+	class := 0
+	for _, r := range v {
+		switch {
+		case unicode.IsLower(r):
+			class |= 1
+		case unicode.IsUpper(r):
+			class |= 2
+		case unicode.IsDigit(r):
+			class |= 4
+		case unicode.IsSpace(r):
+			class |= 8
+		default:
+			class |= 16
+		}
+	}
+	return class
+}
+
+// isTypedValue reports whether "v" seems to be a number or a date rather than a label.
+func isTypedValue(v string) bool {
+	if _, err := strconv.ParseFloat(v, 64); err == nil {
+		return true
+	}
+	digit := false
+	for _, r := range v {
+		switch {
+		case r >= '0' && r <= '9':
+			digit = true
+		case strings.ContainsRune("+-./: ", r):
+		default:
+			return false
+		}
+	}
+	return digit
+}
+
+// crReader converts "\r" to "\n".
+type crReader struct {
+	r io.Reader
+}
+
+func (c *crReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	for i := range p[:n] {
+		if p[i] == '\r' {
+			p[i] = '\n'
+		}
+	}
+	return n, err
+}
+
+// input returns the csv data of "r" decompressed and decoded into UTF-8 without a byte order mark, and the settings detected by WithAuto.
+// A byte order mark takes precedence over the encoding of the dialect.
+// The returned reader must be used instead of "r".
+func (cfg *config) input(r io.Reader, header bool) (io.Reader, *config, error) {
+	r, err := decompress(r, cfg.compression)
+	if err != nil {
+		return nil, nil, err
+	}
+	r, bom := skipBOM(r)
+	if !bom {
+		r = cfg.dialect.Encoding.NewReader(r)
+	}
+	r, cfg, _, err = cfg.autoDetect(r, header, !bom && !cfg.explicitEncoding())
+	return r, cfg, err
+}
+
+// autoDetect applies the format detected by Sniff to a copy of "cfg", if WithAuto is given.
+// The detected header is applied if "header" is true and WithSkipRows is not given.
+// The encoding is detected if "detect" is true.
+// The returned reader must be used instead of "r".
+func (cfg *config) autoDetect(r io.Reader, header bool, detect bool) (io.Reader, *config, Sniffed, error) {
+	if !cfg.auto {
+		return r, cfg, Sniffed{}, nil
+	}
+	s, r, err := sniff(r, cfg.sniffSize, cfg.dialect.Comment, detect)
+	if err != nil {
+		return nil, nil, s, err
+	}
+	c := *cfg
+	c.auto = false
+	c.dialect.Comma = s.Dialect.Comma
+	c.dialect.LazyQuotes = c.dialect.LazyQuotes || s.Dialect.LazyQuotes
+	if header && s.Header && !c.hasTopmergin {
+		c.topmergin = 1
+	}
+	return r, &c, s, nil
+}
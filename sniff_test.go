@@ -0,0 +1,127 @@
+package gotinycsv
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Sniff(t *testing.T) {
+	// normal case 1 (delimiters and headers)
+	{
+		cases := []struct {
+			csv    string
+			comma  rune
+			header bool
+		}{
+			{"No,Name,Birth\n1,Alex,1999.01.01\n2,Bert,2001.02.02\n", ',', true},
+			{"1\tAlex\t41\n2\tBert\t42\n", '\t', false},
+			{"No;Name;Price\n1;Alex;1,5\n2;Bert;2,5\n", ';', true},
+			{"Name|City\nAlex|Paris\nBert|Rome\n", '|', false},
+			{"Name,Memo\nAlex,\"a;b;c;d\"\nBert,\"e;f;g;h\"\n", ',', true},
+			// all texts
+			{"name;city\nalex;tokyo\nbert;kyoto\n", ';', true},
+			{"Code,Email\nab12,a@b.com\ncd34,c@d.org\n", ',', true},
+			{"alex;tokyo\nbert;osaka\n", ';', false},
+			{"Alex Smith,a@b.com\nBert Jones,c@d.org\n", ',', false},
+			{"Alex,Tokyo\nBob,Osaka\n", ',', false},
+		}
+		for _, c := range cases {
+			s, r, err := Sniff(strings.NewReader(c.csv), 0)
+			assert.NoError(t, err)
+			assert.Equal(t, c.comma, s.Dialect.Comma, c.csv)
+			assert.Equal(t, c.header, s.Header, c.csv)
+			assert.Equal(t, "\n", s.LineEnding)
+			all, _ := io.ReadAll(r)
+			assert.Equal(t, c.csv, string(all))
+		}
+	}
+	// normal case 2 (quotes and line endings)
+	{
+		s, _, err := Sniff(strings.NewReader("1,\"Alex\"\r\n2,\"Bert\"\r\n"), 0)
+		assert.NoError(t, err)
+		assert.True(t, s.Quoted)
+		assert.False(t, s.Dialect.LazyQuotes)
+		assert.Equal(t, "\r\n", s.LineEnding)
+
+		s, r, err := Sniff(strings.NewReader("1,\"Alex\" A\r2,Bert\r"), 0)
+		assert.NoError(t, err)
+		assert.True(t, s.Dialect.LazyQuotes)
+		assert.Equal(t, "\r", s.LineEnding)
+		all, _ := io.ReadAll(r)
+		assert.Equal(t, "1,\"Alex\" A\n2,Bert\n", string(all))
+	}
+	// normal case 3 (the incomplete last line of truncated data is ignored)
+	{
+		csv := "1;\"Alex\";41\n2;\"Bert\nB\";42\n"
+		s, r, err := Sniff(strings.NewReader(csv), 18)
+		assert.NoError(t, err)
+		assert.Equal(t, ';', s.Dialect.Comma)
+		assert.False(t, s.Dialect.LazyQuotes)
+		all, _ := io.ReadAll(r)
+		assert.Equal(t, csv, string(all))
+	}
+	// illegal case (io.Reader is nil)
+	{
+		_, r, err := Sniff(nil, 0)
+		assert.EqualError(t, err, "reader is nil")
+		assert.Nil(t, r)
+	}
+}
+
+func Test_WithAuto(t *testing.T) {
+	type csventry struct {
+		No    int
+		Name  string
+		Birth time.Time
+	}
+	tsv := "No\tName\tBirth\n1\tAlex\t1999.01.01\n2\tBert\t2001.02.02\n"
+
+	// normal case 1 (Load)
+	{
+		entries := []csventry{}
+		assert.NoError(t, LoadWith(strings.NewReader(tsv), &entries, WithAuto(0)))
+		assert.Equal(t, 2, len(entries))
+		assert.Equal(t, "Bert", entries[1].Name)
+	}
+	// normal case 2 (explicit WithSkipRows wins over the header)
+	{
+		entries := []csventry{}
+		assert.NoError(t, LoadWith(strings.NewReader(tsv), &entries, WithAuto(0), WithSkipRows(2)))
+		assert.Equal(t, 1, len(entries))
+	}
+	// normal case 3 (the other functions)
+	{
+		dec := NewDecoderWith(strings.NewReader(tsv), WithAuto(0))
+		assert.True(t, dec.Next())
+		assert.Equal(t, []string{"No", "Name", "Birth"}, dec.Header())
+
+		chunked := []csventry{}
+		assert.NoError(t, LoadReaderAtWith(strings.NewReader(tsv), int64(len(tsv)), &chunked, WithAuto(0), WithWorkers(2)))
+		assert.Equal(t, 2, len(chunked))
+
+		cr := strings.ReplaceAll(tsv, "\n", "\r")
+		chunked = []csventry{}
+		assert.NoError(t, LoadReaderAtWith(strings.NewReader(cr), int64(len(cr)), &chunked, WithAuto(0)))
+		assert.Equal(t, "Bert", chunked[1].Name)
+
+		entries, err := LoadAsWith[map[string]string](strings.NewReader(tsv), WithAuto(0))
+		assert.NoError(t, err)
+		assert.Equal(t, "Alex", entries[0]["Name"])
+	}
+	// normal case 4 (LoadVertically does not use the header)
+	{
+		csv := "Name;Alex;Bert\nNo;1;2\n"
+		type csventry struct {
+			Name string
+			No   int
+		}
+
+		entries := []csventry{}
+		assert.NoError(t, LoadVerticallyWith(strings.NewReader(csv), &entries, WithAuto(0), WithSkipColumns(1)))
+		assert.Equal(t, []csventry{{"Alex", 1}, {"Bert", 2}}, entries)
+	}
+}
@@ -0,0 +1,175 @@
+package gotinycsv
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// fieldSpec is the csv settings of a structure field given by the "csv" tag.
+//
+//	`csv:"[name|#N][,option]..."`
+//	`csv:"-"`
+//
+// "#N" binds the field to the N-th (0-based) column, which is the same as the "index=N" option.
+// Columns are not bound by header names, so any other name is ignored and the field is bound by its position.
+// This keeps the structures tagged for other csv libraries, such as `csv:"client_id"`, working.
+// The fields without an index follow the column of the previous field.
+// A field tagged with "-" is excluded from the mapping and does not take a column.
+//
+// options:
+//
+//	index=N
+//	trim=none|both|left|right
+//	key
+//	dup=error|first|last
+//	clone
+//	intern
+//
+// Policies that are not specified fall back to the settings of the load (the package defaults or options),
+// so that specs can be cached per type.
+type fieldSpec struct {
+	index    int
+	hasIndex bool
+	skip     bool
+	trim     TrimPolicy
+	hasTrim  bool
+	key      bool
+	dup      DuplicateKeyPolicy
+	hasDup   bool
+	clone    bool
+	intern   bool
+}
+
+// trimmed applies the trim policy of the field, or the one of "cfg" if it is not specified.
+func (s fieldSpec) trimmed(v string, cfg *config) string {
+	if s.hasTrim {
+		return s.trim.apply(v)
+	}
+	return cfg.trim.apply(v)
+}
+
+// cloned reports whether the string field is cloned by the "clone" tag option or "cfg".
+func (s fieldSpec) cloned(cfg *config) bool {
+	return s.clone || cfg.clone
+}
+
+// interned reports whether the string field is interned by the "intern" tag option or "cfg".
+func (s fieldSpec) interned(cfg *config) bool {
+	return s.intern || cfg.intern
+}
+
+// duplicateKey returns the duplicate key policy of the field, or the one of "cfg" if it is not specified.
+func (s fieldSpec) duplicateKey(cfg *config) DuplicateKeyPolicy {
+	if s.hasDup {
+		return s.dup
+	}
+	return cfg.dup
+}
+
+func parseFieldSpec(f reflect.StructField) (fieldSpec, error) {
+	spec := fieldSpec{}
+	tag, ok := f.Tag.Lookup("csv")
+	if !ok {
+		return spec, nil
+	}
+	if tag == "-" {
+		spec.skip = true
+		return spec, nil
+	}
+	opts := strings.Split(tag, ",")
+	if strings.HasPrefix(opts[0], "#") {
+		index, err := parseColumnIndex(opts[0][1:])
+		if err != nil {
+			return spec, err
+		}
+		spec.index, spec.hasIndex = index, true
+	}
+	for _, opt := range opts[1:] {
+		key, value := opt, ""
+		if i := strings.Index(opt, "="); i >= 0 {
+			key, value = opt[:i], opt[i+1:]
+		}
+		switch key {
+		case "trim":
+			trim, err := parseTrimPolicy(value)
+			if err != nil {
+				return spec, err
+			}
+			spec.trim, spec.hasTrim = trim, true
+		case "index":
+			index, err := parseColumnIndex(value)
+			if err != nil {
+				return spec, err
+			}
+			spec.index, spec.hasIndex = index, true
+		case "key":
+			spec.key = true
+		case "clone":
+			spec.clone = true
+		case "intern":
+			spec.intern = true
+		case "dup":
+			dup, err := parseDuplicateKeyPolicy(value)
+			if err != nil {
+				return spec, err
+			}
+			spec.dup, spec.hasDup = dup, true
+		default:
+			return spec, fmt.Errorf("unknown csv tag option: %s", opt)
+		}
+	}
+	return spec, nil
+}
+
+func parseColumnIndex(s string) (int, error) {
+	index, err := strconv.Atoi(s)
+	if err != nil || index < 0 {
+		return 0, fmt.Errorf("invalid column index: %s", s)
+	}
+	return index, nil
+}
+
+func structFieldSpecs(t reflect.Type) ([]fieldSpec, error) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("elements of slice must be struct")
+	}
+	specs := make([]fieldSpec, t.NumField())
+	for i := range specs {
+		spec, err := parseFieldSpec(t.Field(i))
+		if err != nil {
+			return nil, err
+		}
+		specs[i] = spec
+	}
+	return specs, nil
+}
+
+// columnBindings returns the index of the field bound to each column, or -1 for the columns bound to no field.
+// "explicit" is true if any field has an index or is excluded by "-", in which case unbound columns are ignored.
+func columnBindings(specs []fieldSpec) (columns []int, explicit bool, err error) {
+	next := 0
+	for i, spec := range specs {
+		if spec.skip {
+			explicit = true
+			continue
+		}
+		if spec.hasIndex {
+			explicit = true
+			next = spec.index
+		}
+		for len(columns) <= next {
+			columns = append(columns, -1)
+		}
+		if columns[next] >= 0 {
+			return nil, false, fmt.Errorf("column %d is bound to multiple fields", next)
+		}
+		columns[next] = i
+		next++
+	}
+	return columns, explicit, nil
+}
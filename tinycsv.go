@@ -1,12 +1,10 @@
 package gotinycsv
 
 import (
-	"encoding/csv"
 	"fmt"
 	"io"
 	"reflect"
 	"strconv"
-	"strings"
 	"time"
 	"unsafe"
 )
@@ -20,6 +18,25 @@ func (o options) timeLayout() string {
 	return "2006.1.2"
 }
 
+var timeType = reflect.TypeOf(time.Time{})
+
+func isSupportedType(t reflect.Type) bool {
+	if _, ok := lookupEnum(t); ok {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+	case reflect.Float32:
+	case reflect.Float64:
+	case reflect.String:
+	case reflect.Struct:
+		return t == timeType
+	default:
+		return false
+	}
+	return true
+}
+
 func eachStructFieldRefs(ref reflect.Value) ([][]reflect.Value, error) {
 	elem0t := ref.Index(0).Type()
 	if elem0t.Kind() == reflect.Ptr {
@@ -30,40 +47,40 @@ func eachStructFieldRefs(ref reflect.Value) ([][]reflect.Value, error) {
 	}
 	refs := make([][]reflect.Value, ref.Len())
 	for i := 0; i < ref.Len(); i++ {
-		refs[i] = make([]reflect.Value, elem0t.NumField())
 		elem := ref.Index(i)
 		elemp := reflect.NewAt(elem.Type(), unsafe.Pointer(elem.UnsafeAddr())).Elem()
 		if elemp.Kind() == reflect.Ptr {
 			elemp = elemp.Elem()
 		}
-		for j := 0; j < elem0t.NumField(); j++ {
-			field := elemp.Field(j)
-			ft := field.Type()
-			newref := reflect.NewAt(ft, unsafe.Pointer(field.UnsafeAddr())).Elem()
-			switch ft.Kind() {
-			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			case reflect.Float32:
-			case reflect.Float64:
-			case reflect.String:
-			case reflect.Struct:
-				switch newref.Interface().(type) {
-				case time.Time:
-				default:
-					return nil, fmt.Errorf("Unsupported types are used in structure fields")
-				}
-			default:
-				return nil, fmt.Errorf("Unsupported types are used in structure fields")
-			}
-			refs[i][j] = newref
+		var err error
+		if refs[i], err = structFieldRefs(elemp); err != nil {
+			return nil, err
 		}
 	}
 	return refs, nil
 }
 
+// structFieldRefs returns settable references to the fields of the addressable struct "ref".
+func structFieldRefs(ref reflect.Value) ([]reflect.Value, error) {
+	refs := make([]reflect.Value, ref.NumField())
+	for j := range refs {
+		field := ref.Field(j)
+		ft := field.Type()
+		if !isSupportedType(ft) {
+			return nil, fmt.Errorf("Unsupported types are used in structure fields")
+		}
+		refs[j] = reflect.NewAt(ft, unsafe.Pointer(field.UnsafeAddr())).Elem()
+	}
+	return refs, nil
+}
+
 func setEntityViaRef(ref reflect.Value, timelayout, v string) error {
 	if !ref.CanSet() {
 		return fmt.Errorf("cannot set via reference: %s", v)
 	}
+	if et, ok := lookupEnum(ref.Type()); ok {
+		return et.set(ref, v)
+	}
 	switch ref.Type().Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		iv, _ := strconv.Atoi(v)
@@ -75,15 +92,14 @@ func setEntityViaRef(ref reflect.Value, timelayout, v string) error {
 		fv, _ := strconv.ParseFloat(v, 64)
 		ref.SetFloat(reflect.ValueOf(fv).Float())
 	case reflect.String:
-		ref.SetString(strings.TrimSpace(reflect.ValueOf(v).String()))
+		ref.SetString(reflect.ValueOf(v).String())
 	case reflect.Struct:
-		switch ref.Interface().(type) {
-		case time.Time:
-			t, _ := time.Parse(timelayout, v)
-			ref.Set(reflect.ValueOf(t))
-		default:
+		if ref.Type() != timeType {
 			return fmt.Errorf("Unsupported types are used in structure fields")
 		}
+		// sets through the pointer, because boxing time.Time into an interface allocates
+		t, _ := time.Parse(timelayout, v)
+		*ref.Addr().Interface().(*time.Time) = t
 	default:
 		return fmt.Errorf("Unsupported types are used in structure fields")
 	}
@@ -126,20 +142,63 @@ func ensureSliceCapacity(ref reflect.Value, len int) error {
 // An error occurs when the number of rows read reaches "topmergin + maxrows".
 // if "maxrows" is set to 0, it will attempt to read the entire data regardless of the size of the csv data.
 // "out" is load destination. automatically ensures optimal capacity.
-// The first element of "ops" is time-layout.
-// This function does not emit an error if the conversion from a csv field to a structure field fails.
+// "out" may also be *[]map[string]string or *[]map[string]interface{} keyed by the last line of the top margin.
+// For map[string]interface{}, each column is converted to int64, float64, bool, time.Time or string inferred from its fields.
+// "out" may also be *map[K]T or *map[K]*T keyed by the field tagged with `csv:",key"`.
+// Duplicate keys are handled according to DefaultDuplicateKey or the "dup" tag option of the key field.
+// A structure field is bound to the column at the same position, or the column given by the "#N" or "index=N" tag.
+// Fields tagged with "-" are excluded, and if any field has these tags, the columns bound to no field are ignored.
+// Records with a different number of columns are handled according to DefaultColumns.
+// gzip and bzip2 data is decompressed according to DefaultCompression, and corrupt data is reported as *DecompressError.
+// The first element of "ops" is time-layout. The other formats are set by Dialect.
+// White spaces around each csv field are removed according to DefaultTrim or the "trim" tag option of the field.
+// This function does not emit an error if the conversion from a csv field to a structure field fails,
+// except for unknown labels of the types registered by RegisterEnum.
+// Unexported fields are handled according to DefaultUnexported.
 func Load(r io.Reader, topmergin int, maxrows int, out interface{}, ops ...string) error {
+	return LoadWith(r, out, WithSkipRows(topmergin), WithMaxRows(maxrows), withOps(ops), withTraditional())
+}
+
+// LoadWith loads a CSV with options. It is the same as Load except that the settings are given by "opts",
+// and unexported fields are set only by WithUnexported(UnexportedSet).
+//
+//	err := gotinycsv.LoadWith(r, &out, gotinycsv.WithSkipRows(1), gotinycsv.WithMaxRows(100))
+func LoadWith(r io.Reader, out interface{}, opts ...Option) error {
+	return load(r, out, newConfig(opts...))
+}
+
+func load(r io.Reader, out interface{}, cfg *config) error {
 	if r == nil {
 		return fmt.Errorf("reader is nil")
 	}
+	if refp, ok := mapRefPointer(out); ok {
+		return loadMap(*refp, cfg, func(out interface{}) error {
+			return load(r, out, cfg)
+		})
+	}
+	r, cfg, err := cfg.input(r, true)
+	if err != nil {
+		return err
+	}
+	topmergin, maxrows := cfg.topmergin, cfg.maxrows
 	refp, err := sliceRefPointer(out)
 	if err != nil {
 		return err
 	}
 
-	cr := csv.NewReader(r)
+	cr := cfg.newReader(r)
+
+	if isMapElem(refp.Type().Elem()) {
+		return loadMaps(cr, topmergin, maxrows, *refp, cfg)
+	}
+
+	// compiled settings of the struct fields
+	plan, err := checkedPlan(refp.Type().Elem(), cfg)
+	if err != nil {
+		return err
+	}
 
-	records := make([][]*string, 0, maxrows)
+	records := make([][]string, 0, maxrows)
 
 	rows := 0
 	for ; ; rows++ {
@@ -156,11 +215,10 @@ func Load(r io.Reader, topmergin int, maxrows int, out interface{}, ops ...strin
 		if maxrows > 0 && rows >= topmergin+maxrows {
 			return fmt.Errorf("rows are too large")
 		}
-		r := make([]*string, len(record))
-		for i := range record {
-			r[i] = &record[i]
+		if record, err = fitRecord(cfg.columns, cr, record, len(plan.columns), plan.explicit); err != nil {
+			return err
 		}
-		records = append(records, r)
+		records = append(records, record)
 	}
 	if rows <= topmergin {
 		return fmt.Errorf("topmergin is too large")
@@ -173,24 +231,14 @@ func Load(r io.Reader, topmergin int, maxrows int, out interface{}, ops ...strin
 		return err
 	}
 
-	// create slice of references to struct field
-	refs, err := eachStructFieldRefs(*refp)
-	if err != nil {
+	if err = plan.checkColumns(len(records[0])); err != nil {
 		return err
 	}
 
-	timelayout := options(ops).timeLayout()
-
-	if len(refs[0]) < len(records[0]) {
-		return fmt.Errorf("number of fields in the defined structure may not match the number of fields in the CSV.")
-	}
-
-	for rows := 0; rows < len(records); rows++ {
-		// sets csv record into "out" via references
-		for cols, v := range records[rows] {
-			if err = setEntityViaRef(refs[rows][cols], timelayout, *v); err != nil {
-				return err
-			}
+	for rows, record := range records {
+		// sets csv record into "out" via the plan
+		if err = plan.setRecord(elemRef(*refp, rows), record, cfg); err != nil {
+			return err
 		}
 	}
 
@@ -204,8 +252,17 @@ func Load(r io.Reader, topmergin int, maxrows int, out interface{}, ops ...strin
 // An error occurs when the number of columns read reaches "leftmergin+maxcols".
 // if "maxcols" is set to 0, it will attempt to read the entire data regardless of the size of the csv data.
 // "out" is load destination. automatically ensures optimal capacity.
-// The first element of "ops" is time-layout
-// This function does not emit an error if the conversion from a csv field to a structure field fails.
+// "out" may also be *map[K]T or *map[K]*T keyed by the field tagged with `csv:",key"`.
+// Duplicate keys are handled according to DefaultDuplicateKey or the "dup" tag option of the key field.
+// A structure field is bound to the row (counted from the end of the top margin) at the same position,
+// or the row given by the "#N" or "index=N" tag. Fields tagged with "-" are excluded.
+// Records with a different number of columns are handled according to DefaultColumns.
+// gzip and bzip2 data is decompressed according to DefaultCompression, and corrupt data is reported as *DecompressError.
+// The first element of "ops" is time-layout. The other formats are set by Dialect.
+// White spaces around each csv field are removed according to DefaultTrim or the "trim" tag option of the field.
+// This function does not emit an error if the conversion from a csv field to a structure field fails,
+// except for unknown labels of the types registered by RegisterEnum.
+// Unexported fields are handled according to DefaultUnexported.
 func LoadVertically(r io.Reader, topmergin int, leftmergin int, maxcols int, out interface{}, ops ...string) error {
 	if r == nil {
 		return fmt.Errorf("reader is nil")
@@ -213,12 +270,37 @@ func LoadVertically(r io.Reader, topmergin int, leftmergin int, maxcols int, out
 	if maxcols == 0 {
 		return fmt.Errorf("maxcols is 0")
 	}
+	return LoadVerticallyWith(r, out, WithSkipRows(topmergin), WithSkipColumns(leftmergin), WithMaxColumns(maxcols), withOps(ops), withTraditional())
+}
+
+// LoadVerticallyWith loads a CSV with fileds arranged vertically with options.
+// It is the same as LoadVertically except that the settings are given by "opts", and WithMaxColumns(0) reads all columns.
+// Unexported fields are set only by WithUnexported(UnexportedSet).
+func LoadVerticallyWith(r io.Reader, out interface{}, opts ...Option) error {
+	return loadVertically(r, out, newConfig(opts...))
+}
+
+func loadVertically(r io.Reader, out interface{}, cfg *config) error {
+	if r == nil {
+		return fmt.Errorf("reader is nil")
+	}
+	if refp, ok := mapRefPointer(out); ok {
+		return loadMap(*refp, cfg, func(out interface{}) error {
+			return loadVertically(r, out, cfg)
+		})
+	}
+	r, cfg, err := cfg.input(r, false)
+	if err != nil {
+		return err
+	}
+	topmergin, leftmergin, maxcols := cfg.topmergin, cfg.leftmergin, cfg.maxcols
 	refp, err := sliceRefPointer(out)
 	if err != nil {
 		return err
 	}
 
-	cr := csv.NewReader(r)
+	cr := cfg.newReader(r)
+	cr.ReuseRecord = true
 
 	// discard header
 	discards := topmergin
@@ -248,27 +330,53 @@ func LoadVertically(r io.Reader, topmergin int, leftmergin int, maxcols int, out
 		return err
 	}
 
-	// create slice of references to struct field
-	refs, err := eachStructFieldRefs(*refp)
+	// compiled settings of the struct fields
+	plan, err := checkedPlan(refp.Type().Elem(), cfg)
 	if err != nil {
 		return err
 	}
 
-	timelayout := options(ops).timeLayout()
+	elems := make([]reflect.Value, len(record[leftmergin:]))
+	for cols := range elems {
+		elems[cols] = elemRef(*refp, cols)
+	}
 
-	rows := 0
-	// if topmergin is 0, stored the first line at first.
-	if topmergin == 0 {
-		// sets csv record into "out" via references
+	// sets csv record into the field bound to the row via the plan
+	setRow := func(rows int, record []string) error {
+		i := plan.field(rows)
+		if i < 0 {
+			return nil
+		}
+		width := leftmergin + len(elems)
+		record, err := fitRecord(cfg.columns, cr, record, width, false)
+		if err != nil {
+			return err
+		}
+		// ragged records reach here if FieldsPerRecord is negative
+		switch {
+		case len(record) > width:
+			return columnError(cr, width, fmt.Errorf("%w (%d of %d)", ErrExtraColumns, len(record), width))
+		case len(record) < width:
+			return columnError(cr, len(record)-1, fmt.Errorf("%w (%d of %d)", ErrMissingColumns, len(record), width))
+		}
 		for cols, v := range record[leftmergin:] {
-			if err = setEntityViaRef(refs[cols][rows], timelayout, v); err != nil {
+			if err := plan.setField(elems[cols], i, v, cfg); err != nil {
 				return err
 			}
 		}
+		return nil
+	}
+
+	rows := 0
+	// if topmergin is 0, stored the first line at first.
+	if topmergin == 0 {
+		if err = setRow(rows, record); err != nil {
+			return err
+		}
 		rows++
 	}
 
-	for ; rows < len(refs[0]); rows++ {
+	for ; rows < len(plan.columns); rows++ {
 		record, err = cr.Read()
 		if err == io.EOF {
 			break
@@ -277,11 +385,8 @@ func LoadVertically(r io.Reader, topmergin int, leftmergin int, maxcols int, out
 			return err
 		}
 
-		// sets csv record into "out" via references
-		for cols, v := range record[leftmergin:] {
-			if err = setEntityViaRef(refs[cols][rows], timelayout, v); err != nil {
-				return err
-			}
+		if err = setRow(rows, record); err != nil {
+			return err
 		}
 	}
 
@@ -0,0 +1,53 @@
+package gotinycsv
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// TrimPolicy specifies which white spaces around a csv field are removed before conversion.
+// White spaces are determined by unicode.IsSpace, so full-width spaces (U+3000) are also removed.
+type TrimPolicy int
+
+const (
+	// TrimNone keeps the csv field as it is.
+	TrimNone TrimPolicy = iota
+	// TrimBoth removes leading and trailing white spaces.
+	TrimBoth
+	// TrimLeft removes leading white spaces.
+	TrimLeft
+	// TrimRight removes trailing white spaces.
+	TrimRight
+)
+
+// DefaultTrim is the trim policy applied to the structure fields that do not have a "trim" tag option.
+// It is fixed at the start of each call, and WithTrim overrides it per call.
+// The policy is applied to all field types, not only to strings.
+var DefaultTrim = TrimBoth
+
+func (p TrimPolicy) apply(v string) string {
+	switch p {
+	case TrimBoth:
+		return strings.TrimFunc(v, unicode.IsSpace)
+	case TrimLeft:
+		return strings.TrimLeftFunc(v, unicode.IsSpace)
+	case TrimRight:
+		return strings.TrimRightFunc(v, unicode.IsSpace)
+	}
+	return v
+}
+
+func parseTrimPolicy(s string) (TrimPolicy, error) {
+	switch s {
+	case "none":
+		return TrimNone, nil
+	case "both":
+		return TrimBoth, nil
+	case "left":
+		return TrimLeft, nil
+	case "right":
+		return TrimRight, nil
+	}
+	return TrimNone, fmt.Errorf("unknown trim policy: %s", s)
+}
@@ -0,0 +1,116 @@
+package gotinycsv
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TrimPolicy(t *testing.T) {
+	// normal case
+	{
+		v := " \t　a b　\t "
+		assert.Equal(t, v, TrimNone.apply(v))
+		assert.Equal(t, "a b", TrimBoth.apply(v))
+		assert.Equal(t, "a b　\t ", TrimLeft.apply(v))
+		assert.Equal(t, " \t　a b", TrimRight.apply(v))
+	}
+	// illegal case (unknown policy)
+	{
+		_, err := parseTrimPolicy("middle")
+		assert.EqualError(t, err, "unknown trim policy: middle")
+	}
+}
+
+func Test_Load_trim(t *testing.T) {
+	// normal case 1 (trimmed before any conversion)
+	{
+		csv := ` 41 ,　Alex　, 1.5
+`
+		type csventry struct {
+			age    int
+			name   string
+			height float64
+		}
+
+		entries := []csventry{}
+		err := Load(strings.NewReader(csv), 0, 10, &entries)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 41, entries[0].age)
+		assert.Equal(t, "Alex", entries[0].name)
+		assert.Equal(t, 1.5, entries[0].height)
+	}
+	// normal case 2 (per field policy)
+	{
+		csv := ` a , b , c , d ,
+`
+		type csventry struct {
+			none  string `csv:",trim=none"`
+			both  string `csv:",trim=both"`
+			left  string `csv:",trim=left"`
+			right string `csv:",trim=right"`
+			_     string
+		}
+
+		entries := []csventry{}
+		err := Load(strings.NewReader(csv), 0, 10, &entries)
+
+		assert.NoError(t, err)
+		assert.Equal(t, " a ", entries[0].none)
+		assert.Equal(t, "b", entries[0].both)
+		assert.Equal(t, "c ", entries[0].left)
+		assert.Equal(t, " d", entries[0].right)
+	}
+	// normal case 3 (global policy)
+	{
+		csv := ` a , b
+`
+		type csventry struct {
+			a string
+			b string `csv:",trim=both"`
+		}
+
+		DefaultTrim = TrimNone
+		entries := []csventry{}
+		err := Load(strings.NewReader(csv), 0, 10, &entries)
+		DefaultTrim = TrimBoth
+
+		assert.NoError(t, err)
+		assert.Equal(t, " a ", entries[0].a)
+		assert.Equal(t, "b", entries[0].b)
+	}
+	// normal case 4 (LoadVertically)
+	{
+		csv := `Age, 41 , 42
+Name, Alex , Bert
+`
+		type csventry struct {
+			age  int
+			name string `csv:",trim=left"`
+		}
+
+		entries := []csventry{}
+		err := LoadVertically(strings.NewReader(csv), 0, 1, 10, &entries)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 41, entries[0].age)
+		assert.Equal(t, 42, entries[1].age)
+		assert.Equal(t, "Alex ", entries[0].name)
+		assert.Equal(t, "Bert", entries[1].name)
+	}
+	// illegal case (unknown tag option)
+	{
+		csv := `a
+`
+		type csventry struct {
+			a string `csv:",trim=middle"`
+		}
+
+		entries := []csventry{}
+		err := Load(strings.NewReader(csv), 0, 10, &entries)
+
+		assert.EqualError(t, err, "unknown trim policy: middle")
+	}
+}